@@ -2,9 +2,12 @@ package langfuse
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"net/http"
+	"slices"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/ezardev-team/langfuse-go/internal/pkg/api"
 	"github.com/ezardev-team/langfuse-go/model"
@@ -12,6 +15,19 @@ import (
 
 const cacheMetadataKey = "cache_key"
 
+const (
+	// cacheBatchChunkSize caps the number of keys sent in one any-of filter so
+	// the encoded filter stays well inside common URL length limits.
+	cacheBatchChunkSize = 50
+	// cacheBatchPageSize is the page size used while resolving a chunk.
+	cacheBatchPageSize = 100
+	// cacheBatchMaxPages bounds how many pages a single chunk may walk before
+	// its unresolved keys are handed to the per-key fallback.
+	cacheBatchMaxPages = 10
+	// cacheBatchConcurrency bounds the per-key fallback lookups in flight.
+	cacheBatchConcurrency = 8
+)
+
 // GenerationCache defines the cache lookup helper on top of the public API client.
 type GenerationCache interface {
 	// FindCachedGeneration searches for a GENERATION observation that matches the provided cache key
@@ -26,14 +42,112 @@ type GenerationCache interface {
 type GenerationCacheOptions struct {
 	// Name optionally scopes the lookup to a specific observation/function name.
 	Name string
+	// MaxAge ignores generations that started longer than MaxAge ago. Zero
+	// means no limit.
+	MaxAge time.Duration
+	// Environment optionally scopes the lookup to a Langfuse environment.
+	Environment string
+	// Model optionally scopes the lookup to a model name.
+	Model string
+	// PromptVersion optionally scopes the lookup to a prompt version. Zero
+	// means any version.
+	PromptVersion int
+	// MinLevel ignores generations logged below this level, in the order
+	// DEBUG < DEFAULT < WARNING < ERROR.
+	MinLevel model.ObservationLevel
+	// ExcludeErrors ignores generations that ended with ERROR level.
+	ExcludeErrors bool
+}
+
+// withDefaultEnvironment scopes options to the client environment when they
+// name none. options itself is not modified.
+func (l *Langfuse) withDefaultEnvironment(options *GenerationCacheOptions) *GenerationCacheOptions {
+	env := l.environment()
+	if env == "" || (options != nil && options.Environment != "") {
+		return options
+	}
+
+	scoped := GenerationCacheOptions{}
+	if options != nil {
+		scoped = *options
+	}
+	scoped.Environment = env
+
+	return &scoped
+}
+
+// observationLevels lists the observation levels from lowest to highest.
+var observationLevels = []model.ObservationLevel{
+	model.ObservationLevelDebug,
+	model.ObservationLevelDefault,
+	model.ObservationLevelWarning,
+	model.ObservationLevelError,
+}
+
+// allowedLevels returns the levels a cached generation may have under o, or
+// nil when every level is allowed.
+func (o *GenerationCacheOptions) allowedLevels() ([]model.ObservationLevel, error) {
+	if o == nil || (o.MinLevel == "" && !o.ExcludeErrors) {
+		return nil, nil
+	}
+
+	minIndex := 0
+	if o.MinLevel != "" {
+		minIndex = slices.Index(observationLevels, o.MinLevel)
+		if minIndex < 0 {
+			return nil, fmt.Errorf("unknown observation level: %q", o.MinLevel)
+		}
+	}
+
+	levels := make([]model.ObservationLevel, 0, len(observationLevels))
+	for _, level := range observationLevels[minIndex:] {
+		if o.ExcludeErrors && level == model.ObservationLevelError {
+			continue
+		}
+		levels = append(levels, level)
+	}
+
+	if len(levels) == 0 {
+		return nil, fmt.Errorf("cache options exclude every observation level")
+	}
+
+	return levels, nil
 }
 
-type observationFilter struct {
-	Type     string      `json:"type"`
-	Column   string      `json:"column"`
-	Operator string      `json:"operator"`
-	Value    interface{} `json:"value,omitempty"`
-	Key      string      `json:"key,omitempty"`
+// matches reports whether obs satisfies o. The same scoping is sent to the
+// API as filters; this re-check keeps a lenient server from handing back a
+// stale or out-of-scope generation.
+func (o *GenerationCacheOptions) matches(obs *model.ObservationView, levels []model.ObservationLevel, now time.Time) bool {
+	if o == nil {
+		return true
+	}
+
+	if o.Name != "" && obs.Name != "" && obs.Name != o.Name {
+		return false
+	}
+	if o.Model != "" && obs.Model != o.Model {
+		return false
+	}
+	if o.Environment != "" && obs.Environment != "" && obs.Environment != o.Environment {
+		return false
+	}
+	if o.PromptVersion != 0 && obs.PromptVersion != o.PromptVersion {
+		return false
+	}
+	if o.MaxAge > 0 && obs.StartTime != nil && obs.StartTime.Before(now.Add(-o.MaxAge)) {
+		return false
+	}
+	if levels != nil {
+		level := obs.Level
+		if level == "" {
+			level = model.ObservationLevelDefault
+		}
+		if !slices.Contains(levels, level) {
+			return false
+		}
+	}
+
+	return true
 }
 
 var _ GenerationCache = (*Langfuse)(nil)
@@ -44,97 +158,95 @@ func (l *Langfuse) FindCachedGeneration(ctx context.Context, cacheKey string, op
 		return nil, fmt.Errorf("cache key is required")
 	}
 
-	return l.findCachedGeneration(ctx, cacheKey, options)
+	return l.findCachedGeneration(ctx, cacheKey, l.withDefaultEnvironment(options))
 }
 
 // FindCachedGenerationBatch searches for multiple GENERATION observations that match the provided cache keys.
 // Returns a map of cacheKey -> ObservationView for found entries. Keys not found are omitted from the result.
+//
+// Keys are looked up in chunks of cacheBatchChunkSize with a single `any of`
+// metadata filter per chunk. Chunks the server cannot answer that way (the
+// filter is rejected, or the page budget runs out before every key is
+// resolved) fall back to per-key lookups with at most cacheBatchConcurrency
+// requests in flight.
 func (l *Langfuse) FindCachedGenerationBatch(ctx context.Context, cacheKeys []string, options *GenerationCacheOptions) (map[string]*model.ObservationView, error) {
 	if len(cacheKeys) == 0 {
 		return make(map[string]*model.ObservationView), nil
 	}
 
-	result := make(map[string]*model.ObservationView)
-
+	keys := make([]string, 0, len(cacheKeys))
+	seen := make(map[string]struct{}, len(cacheKeys))
 	for _, cacheKey := range cacheKeys {
 		if cacheKey == "" {
 			return nil, fmt.Errorf("cache key is required")
 		}
+		if _, ok := seen[cacheKey]; ok {
+			continue
+		}
+		seen[cacheKey] = struct{}{}
+		keys = append(keys, cacheKey)
+	}
+
+	options = l.withDefaultEnvironment(options)
+	result := make(map[string]*model.ObservationView)
+	var unresolved []string
 
-		obs, err := l.findCachedGeneration(ctx, cacheKey, options)
+	for start := 0; start < len(keys); start += cacheBatchChunkSize {
+		end := min(start+cacheBatchChunkSize, len(keys))
+
+		pending, err := l.findCachedGenerationChunk(ctx, keys[start:end], options, result)
 		if err != nil {
 			return nil, err
 		}
+		unresolved = append(unresolved, pending...)
+	}
 
-		if obs != nil {
-			result[cacheKey] = obs
-		}
+	if len(unresolved) == 0 {
+		return result, nil
+	}
+
+	found, err := l.findCachedGenerationParallel(ctx, unresolved, options)
+	if err != nil {
+		return nil, err
+	}
+	for cacheKey, obs := range found {
+		result[cacheKey] = obs
 	}
 
 	return result, nil
 }
 
 func (l *Langfuse) findCachedGeneration(ctx context.Context, cacheKey string, options *GenerationCacheOptions) (*model.ObservationView, error) {
-	filters := []observationFilter{
-		{
-			Type:     "string",
-			Column:   "type",
-			Operator: "=",
-			Value:    model.ObservationTypeGeneration,
-		},
-		{
-			// `matches` (vs `=`) tolerates the surrounding JSON quotes that
-			// older traces persisted around scalar metadata values, so both a
-			// stored `"<key>"` and a clean `<key>` are returned as candidates.
-			// The exact check below is the real guarantee.
-			Type:     "stringObject",
-			Column:   "metadata",
-			Key:      cacheMetadataKey,
-			Operator: "matches",
-			Value:    cacheKey,
-		},
-	}
-
-	if options != nil && options.Name != "" {
-		filters = append(filters, observationFilter{
-			Type:     "string",
-			Column:   "name",
-			Operator: "=",
-			Value:    options.Name,
-		})
-	}
-
-	filterString, err := json.Marshal(filters)
+	// `matches` (vs `=`) tolerates the surrounding JSON quotes that older
+	// traces persisted around scalar metadata values, so both a stored
+	// `"<key>"` and a clean `<key>` are returned as candidates. The exact
+	// check below is the real guarantee.
+	metadataFilter := ObservationColumnMetadata.Key(cacheMetadataKey).Matches(cacheKey)
+
+	res, err := l.queryCachedGenerations(ctx, metadataFilter, options, 1, "")
 	if err != nil {
-		return nil, fmt.Errorf("failed to encode observation filters: %w", err)
+		return nil, err
 	}
 
-	limit := 1
-	req := api.ObservationsRequest{
-		Limit:          &limit,
-		Filter:         string(filterString),
-		Fields:         api.ObservationFieldsAll,
-		ExpandMetadata: cacheMetadataKey,
+	if !res.IsSuccess() {
+		return nil, observationsStatusError(res)
 	}
 
-	res := api.ObservationsResponse{}
-	if err := l.client.Observations(ctx, &req, &res); err != nil {
+	levels, err := options.allowedLevels()
+	if err != nil {
 		return nil, err
 	}
 
-	if !res.IsSuccess() {
-		if res.RawBody != nil {
-			return nil, fmt.Errorf("observations request failed with status code: %d body=%s", res.Code, *res.RawBody)
-		}
-		return nil, fmt.Errorf("observations request failed with status code: %d", res.Code)
-	}
-
+	now := time.Now()
 	for i := range res.Data {
 		obs := &res.Data[i]
 		foundCacheKey, ok := extractCacheKeyFromMetadata(obs.Metadata)
 		if !ok || normalizeMetadataString(foundCacheKey) != cacheKey {
 			continue
 		}
+		if !options.matches(obs, levels, now) {
+			continue
+		}
 
 		return obs, nil
 	}
@@ -142,6 +254,202 @@ func (l *Langfuse) findCachedGeneration(ctx context.Context, cacheKey string, op
 	return nil, nil
 }
 
+// findCachedGenerationChunk resolves a chunk of cache keys with one `any of`
+// metadata filter, following the cursor until every key is found or the
+// results run out. Hits are written into result. The returned keys could not
+// be answered by the filter and must be looked up one by one.
+func (l *Langfuse) findCachedGenerationChunk(ctx context.Context, cacheKeys []string, options *GenerationCacheOptions, result map[string]*model.ObservationView) ([]string, error) {
+	pending := make(map[string]struct{}, len(cacheKeys))
+	// Legacy traces stored scalar metadata JSON-quoted, so each key is sent
+	// both raw and quoted; the exact check below maps either back to the key.
+	values := make([]string, 0, 2*len(cacheKeys))
+	for _, cacheKey := range cacheKeys {
+		pending[cacheKey] = struct{}{}
+		values = append(values, cacheKey, strconv.Quote(cacheKey))
+	}
+
+	filter := ObservationColumnMetadata.Key(cacheMetadataKey).AnyOf(values...)
+
+	levels, err := options.allowedLevels()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	cursor := ""
+	for page := 0; page < cacheBatchMaxPages; page++ {
+		res, err := l.queryCachedGenerations(ctx, filter, options, cacheBatchPageSize, cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		if !res.IsSuccess() {
+			if res.Code == http.StatusBadRequest {
+				// The server cannot evaluate the any-of filter.
+				return cacheKeys, nil
+			}
+			return nil, observationsStatusError(res)
+		}
+
+		for i := range res.Data {
+			obs := &res.Data[i]
+			foundCacheKey, ok := extractCacheKeyFromMetadata(obs.Metadata)
+			if !ok {
+				continue
+			}
+
+			cacheKey := normalizeMetadataString(foundCacheKey)
+			if _, ok := pending[cacheKey]; !ok {
+				continue
+			}
+			if !options.matches(obs, levels, now) {
+				continue
+			}
+
+			result[cacheKey] = obs
+			delete(pending, cacheKey)
+		}
+
+		if len(pending) == 0 || res.Meta.Cursor == nil || *res.Meta.Cursor == "" {
+			return nil, nil
+		}
+		cursor = *res.Meta.Cursor
+	}
+
+	unresolved := make([]string, 0, len(pending))
+	for _, cacheKey := range cacheKeys {
+		if _, ok := pending[cacheKey]; ok {
+			unresolved = append(unresolved, cacheKey)
+		}
+	}
+
+	return unresolved, nil
+}
+
+// findCachedGenerationParallel looks up each key with findCachedGeneration,
+// running at most cacheBatchConcurrency requests at a time. The first error
+// cancels the remaining lookups.
+func (l *Langfuse) findCachedGenerationParallel(ctx context.Context, cacheKeys []string, options *GenerationCacheOptions) (map[string]*model.ObservationView, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+
+	result := make(map[string]*model.ObservationView)
+	sem := make(chan struct{}, cacheBatchConcurrency)
+
+	for _, cacheKey := range cacheKeys {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(cacheKey string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			obs, err := l.findCachedGeneration(ctx, cacheKey, options)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			if obs != nil {
+				result[cacheKey] = obs
+			}
+		}(cacheKey)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// queryCachedGenerations fetches one page of GENERATION observations matching
+// the given metadata filter and the scoping in options.
+func (l *Langfuse) queryCachedGenerations(ctx context.Context, metadataFilter FilterCondition, options *GenerationCacheOptions, limit int, cursor string) (*api.ObservationsResponse, error) {
+	filters := []FilterCondition{
+		ObservationColumnType.Eq(string(model.ObservationTypeGeneration)),
+		metadataFilter,
+	}
+
+	req := api.ObservationsRequest{
+		Cursor:         cursor,
+		Limit:          &limit,
+		Fields:         api.ObservationFieldsAll,
+		ExpandMetadata: cacheMetadataKey,
+	}
+
+	if options != nil {
+		if options.Name != "" {
+			filters = append(filters, ObservationColumnName.Eq(options.Name))
+		}
+
+		if options.Model != "" {
+			filters = append(filters, ObservationColumnModel.Eq(options.Model))
+		}
+
+		if options.PromptVersion != 0 {
+			filters = append(filters, ObservationColumnPromptVersion.Eq(float64(options.PromptVersion)))
+		}
+
+		levels, err := options.allowedLevels()
+		if err != nil {
+			return nil, err
+		}
+		if levels != nil {
+			values := make([]string, len(levels))
+			for i, level := range levels {
+				values[i] = string(level)
+			}
+			filters = append(filters, ObservationColumnLevel.AnyOf(values...))
+		}
+
+		if options.Environment != "" {
+			req.Environment = []string{options.Environment}
+		}
+
+		if options.MaxAge > 0 {
+			from := time.Now().Add(-options.MaxAge).UTC()
+			req.FromStartTime = &from
+		}
+	}
+
+	filter, err := encodeFilter(filters)
+	if err != nil {
+		return nil, err
+	}
+	req.Filter = filter
+
+	res := api.ObservationsResponse{}
+	if err := l.client.Observations(ctx, &req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
 // normalizeMetadataString undoes the optional JSON string quoting that some
 // Langfuse storage / expandMetadata paths apply to scalar metadata values, so a
 // stored `"<key>"` and a stored `<key>` both compare equal to the raw cache key.
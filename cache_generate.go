@@ -0,0 +1,202 @@
+package langfuse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+const cacheHitEventName = "cache-hit"
+
+// CachedGenerateOptions configures CachedGenerate.
+type CachedGenerateOptions struct {
+	// Generation is the template for the generation recorded on a miss (name,
+	// trace, model, input, ...). It is copied, never modified. Its Metadata is
+	// extended with the cache key, so it must be nil, model.M,
+	// map[string]any or map[string]string.
+	Generation *model.Generation
+	// ParentID optionally nests the recorded generation or cache-hit event
+	// under an existing observation.
+	ParentID *string
+	// Lookup is where cached generations are searched, e.g. a
+	// MemoryGenerationCache wrapping the client. Defaults to the client.
+	Lookup GenerationCache
+	// CacheOptions scopes the lookup. When nil the lookup is scoped to
+	// Generation.Name, if set.
+	CacheOptions *GenerationCacheOptions
+}
+
+// GenerateFunc produces a fresh result on a cache miss. g is the generation
+// about to be recorded; fn may fill in fields such as Model, Usage or
+// CompletionStartTime. g.Output defaults to the returned value.
+type GenerateFunc[T any] func(ctx context.Context, g *model.Generation) (T, error)
+
+// CachedGenerate is a read-through cache around an LLM call.
+//
+// It looks up a GENERATION observation recorded under cacheKey. On a hit the
+// cached output is decoded into T and a cache-hit event linking to the
+// original generation is recorded. On a miss fn is called and its result is
+// recorded as a generation whose metadata carries cacheKey under the same
+// key FindCachedGeneration filters on. The returned bool reports a hit.
+//
+// A cached output that cannot be decoded into T is treated as a miss.
+func CachedGenerate[T any](ctx context.Context, lf *Langfuse, cacheKey string, opts *CachedGenerateOptions, fn GenerateFunc[T]) (T, bool, error) {
+	var zero T
+
+	if cacheKey == "" {
+		return zero, false, fmt.Errorf("cache key is required")
+	}
+	if fn == nil {
+		return zero, false, fmt.Errorf("generate function is required")
+	}
+	if opts == nil {
+		opts = &CachedGenerateOptions{}
+	}
+
+	var template model.Generation
+	if opts.Generation != nil {
+		template = *opts.Generation
+	}
+
+	metadata, err := withCacheKeyMetadata(template.Metadata, cacheKey)
+	if err != nil {
+		return zero, false, err
+	}
+
+	lookup := opts.Lookup
+	if lookup == nil {
+		lookup = lf
+	}
+
+	cacheOptions := opts.CacheOptions
+	if cacheOptions == nil && template.Name != "" {
+		cacheOptions = &GenerationCacheOptions{Name: template.Name}
+	}
+
+	hit, err := lookup.FindCachedGeneration(ctx, cacheKey, cacheOptions)
+	if err != nil {
+		return zero, false, err
+	}
+
+	if hit != nil {
+		value, err := decodeCachedOutput[T](hit.Output)
+		if err == nil {
+			if err := recordCacheHit(lf, cacheKey, &template, hit, opts.ParentID); err != nil {
+				return zero, false, err
+			}
+			return value, true, nil
+		}
+		log.Printf("cached generation %s for cache key %q could not be decoded, regenerating: %v", hit.ID, cacheKey, err)
+	}
+
+	g := template
+	if g.StartTime == nil {
+		start := time.Now().UTC()
+		g.StartTime = &start
+	}
+
+	value, fnErr := fn(ctx, &g)
+
+	if g.EndTime == nil {
+		end := time.Now().UTC()
+		g.EndTime = &end
+	}
+
+	if fnErr != nil {
+		// Failed generations are recorded for visibility but without the
+		// cache key, so they are never served as a cached answer.
+		g.Level = model.ObservationLevelError
+		g.StatusMessage = fnErr.Error()
+		if _, err := lf.Generation(&g, opts.ParentID); err != nil {
+			return zero, false, err
+		}
+		return zero, false, fnErr
+	}
+
+	if g.Output == nil {
+		g.Output = value
+	}
+	g.Metadata = metadata
+
+	if _, err := lf.Generation(&g, opts.ParentID); err != nil {
+		return zero, false, err
+	}
+
+	if invalidator, ok := lookup.(interface{ Invalidate(cacheKey string) }); ok {
+		invalidator.Invalidate(cacheKey)
+	}
+
+	return value, false, nil
+}
+
+// recordCacheHit records an event on the caller's trace pointing at the
+// generation whose output was reused.
+func recordCacheHit(lf *Langfuse, cacheKey string, template *model.Generation, hit *model.ObservationView, parentID *string) error {
+	_, err := lf.Event(&model.Event{
+		TraceID: template.TraceID,
+		Name:    cacheHitEventName,
+		Input:   template.Input,
+		Output:  hit.Output,
+		Metadata: map[string]any{
+			"cache_hit_key":         cacheKey,
+			"cached_observation_id": hit.ID,
+			"cached_trace_id":       hit.TraceID,
+			"cached_name":           hit.Name,
+		},
+		Version: template.Version,
+	}, parentID)
+
+	return err
+}
+
+// decodeCachedOutput converts a cached observation output, as decoded from
+// the API's JSON, into T.
+func decodeCachedOutput[T any](output any) (T, error) {
+	var value T
+
+	if v, ok := output.(T); ok {
+		return v, nil
+	}
+
+	data, err := json.Marshal(output)
+	if err != nil {
+		return value, fmt.Errorf("failed to encode cached output: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, fmt.Errorf("failed to decode cached output into %T: %w", value, err)
+	}
+
+	return value, nil
+}
+
+// withCacheKeyMetadata returns a copy of metadata with cacheMetadataKey set,
+// leaving the caller's map untouched.
+func withCacheKeyMetadata(metadata any, cacheKey string) (any, error) {
+	switch m := metadata.(type) {
+	case nil:
+		return map[string]any{cacheMetadataKey: cacheKey}, nil
+	case model.M:
+		return withCacheKeyMetadata(map[string]any(m), cacheKey)
+	case map[string]any:
+		out := make(map[string]any, len(m)+1)
+		for k, v := range m {
+			out[k] = v
+		}
+		out[cacheMetadataKey] = cacheKey
+		return out, nil
+	case map[string]string:
+		out := make(map[string]string, len(m)+1)
+		for k, v := range m {
+			out[k] = v
+		}
+		out[cacheMetadataKey] = cacheKey
+		return out, nil
+	default:
+		return nil, fmt.Errorf("generation metadata of type %T cannot carry %s", metadata, cacheMetadataKey)
+	}
+}
@@ -0,0 +1,264 @@
+package langfuse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/ezardev-team/langfuse-go/model"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracev1 "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// cacheStandIn serves observation lookups from a fixed list and records every
+// OTLP export it receives.
+type cacheStandIn struct {
+	mu           sync.Mutex
+	observations []map[string]any
+	exports      [][]byte
+}
+
+func newCacheStandIn(t *testing.T, observations []map[string]any) (*cacheStandIn, *Langfuse) {
+	t.Helper()
+	s := &cacheStandIn{observations: observations}
+
+	newStandIn(t, func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		switch r.URL.Path {
+		case "/api/public/v2/observations":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": s.observations,
+				"meta": map[string]any{"cursor": nil},
+			})
+		case "/api/public/otel/v1/traces":
+			b, _ := io.ReadAll(r.Body)
+			s.exports = append(s.exports, b)
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	return s, New(context.Background())
+}
+
+func (s *cacheStandIn) spans(t *testing.T) []*tracev1.Span {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var spans []*tracev1.Span
+	for _, body := range s.exports {
+		var req coltracepb.ExportTraceServiceRequest
+		if err := proto.Unmarshal(body, &req); err != nil {
+			t.Fatalf("unmarshal OTLP body: %v", err)
+		}
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				spans = append(spans, ss.Spans...)
+			}
+		}
+	}
+	return spans
+}
+
+func spanAttr(span *tracev1.Span, key string) (string, bool) {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value.GetStringValue(), true
+		}
+	}
+	return "", false
+}
+
+// findObservationSpan returns the span with the given name and Langfuse
+// observation type. Auto-created traces share their first observation's name,
+// so the name alone is ambiguous.
+func findObservationSpan(spans []*tracev1.Span, name, obsType string) *tracev1.Span {
+	for _, s := range spans {
+		if v, _ := spanAttr(s, "langfuse.observation.type"); s.Name == name && v == obsType {
+			return s
+		}
+	}
+	return nil
+}
+
+type summary struct {
+	Text  string `json:"text"`
+	Score int    `json:"score"`
+}
+
+func TestCachedGenerate_MissRecordsCacheKey(t *testing.T) {
+	standIn, lf := newCacheStandIn(t, nil)
+	ctx := context.Background()
+
+	calls := 0
+	got, hit, err := CachedGenerate(ctx, lf, "key-1", &CachedGenerateOptions{
+		Generation: &model.Generation{
+			Name:     "summarize",
+			Model:    "test-model",
+			Metadata: model.M{"team": "search"},
+		},
+	}, func(ctx context.Context, g *model.Generation) (summary, error) {
+		calls++
+		g.Usage = model.Usage{Input: 3, Output: 4}
+		return summary{Text: "fresh", Score: 7}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hit {
+		t.Error("expected miss")
+	}
+	if calls != 1 || got.Text != "fresh" {
+		t.Errorf("calls=%d got=%+v, want one call returning fresh", calls, got)
+	}
+
+	lf.Flush(ctx)
+
+	gen := findObservationSpan(standIn.spans(t), "summarize", "generation")
+	if gen == nil {
+		t.Fatal("generation span not exported")
+	}
+	if v, ok := spanAttr(gen, "langfuse.observation.metadata."+cacheMetadataKey); !ok || v != "key-1" {
+		t.Errorf("cache key attribute=%q (ok=%v), want key-1", v, ok)
+	}
+	if v, ok := spanAttr(gen, "langfuse.observation.metadata.team"); !ok || v != "search" {
+		t.Errorf("team attribute=%q (ok=%v), want search", v, ok)
+	}
+	if v, _ := spanAttr(gen, "langfuse.observation.output"); v != `{"text":"fresh","score":7}` {
+		t.Errorf("output=%q", v)
+	}
+}
+
+func TestCachedGenerate_HitDecodesOutputAndRecordsEvent(t *testing.T) {
+	standIn, lf := newCacheStandIn(t, []map[string]any{
+		{
+			"id":       "obs-orig",
+			"traceId":  "trace-orig",
+			"type":     "GENERATION",
+			"name":     "summarize",
+			"output":   map[string]any{"text": "cached", "score": 9},
+			"metadata": map[string]any{cacheMetadataKey: "key-1"},
+		},
+	})
+	ctx := context.Background()
+
+	got, hit, err := CachedGenerate(ctx, lf, "key-1", &CachedGenerateOptions{
+		Generation: &model.Generation{Name: "summarize"},
+	}, func(ctx context.Context, g *model.Generation) (summary, error) {
+		t.Fatal("generate must not be called on a hit")
+		return summary{}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !hit || got.Text != "cached" || got.Score != 9 {
+		t.Fatalf("hit=%v got=%+v, want cached hit", hit, got)
+	}
+
+	lf.Flush(ctx)
+
+	event := findObservationSpan(standIn.spans(t), cacheHitEventName, "event")
+	if event == nil {
+		t.Fatal("cache-hit event not exported")
+	}
+	if v, _ := spanAttr(event, "langfuse.observation.metadata.cached_observation_id"); v != "obs-orig" {
+		t.Errorf("cached_observation_id=%q, want obs-orig", v)
+	}
+	if _, ok := spanAttr(event, "langfuse.observation.metadata."+cacheMetadataKey); ok {
+		t.Error("cache-hit event must not carry the cache key itself")
+	}
+}
+
+func TestCachedGenerate_UndecodableHitRegenerates(t *testing.T) {
+	_, lf := newCacheStandIn(t, []map[string]any{
+		{
+			"id":       "obs-orig",
+			"type":     "GENERATION",
+			"output":   "not an object",
+			"metadata": map[string]any{cacheMetadataKey: "key-1"},
+		},
+	})
+
+	got, hit, err := CachedGenerate(context.Background(), lf, "key-1", nil,
+		func(ctx context.Context, g *model.Generation) (summary, error) {
+			return summary{Text: "fresh"}, nil
+		})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hit || got.Text != "fresh" {
+		t.Errorf("hit=%v got=%+v, want regenerated value", hit, got)
+	}
+}
+
+func TestCachedGenerate_ErrorNotCached(t *testing.T) {
+	standIn, lf := newCacheStandIn(t, nil)
+	ctx := context.Background()
+	boom := errors.New("boom")
+
+	_, _, err := CachedGenerate(ctx, lf, "key-1", &CachedGenerateOptions{
+		Generation: &model.Generation{Name: "summarize"},
+	}, func(ctx context.Context, g *model.Generation) (string, error) {
+		return "", boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("err=%v, want boom", err)
+	}
+
+	lf.Flush(ctx)
+
+	gen := findObservationSpan(standIn.spans(t), "summarize", "generation")
+	if gen == nil {
+		t.Fatal("failed generation not exported")
+	}
+	if _, ok := spanAttr(gen, "langfuse.observation.metadata."+cacheMetadataKey); ok {
+		t.Error("failed generation must not carry the cache key")
+	}
+	if v, _ := spanAttr(gen, "langfuse.observation.level"); v != string(model.ObservationLevelError) {
+		t.Errorf("level=%q, want ERROR", v)
+	}
+}
+
+func TestCachedGenerate_InvalidatesMemoryCache(t *testing.T) {
+	_, lf := newCacheStandIn(t, nil)
+	mem := NewMemoryGenerationCache(lf, nil)
+	ctx := context.Background()
+
+	_, _, err := CachedGenerate(ctx, lf, "key-1", &CachedGenerateOptions{Lookup: mem},
+		func(ctx context.Context, g *model.Generation) (string, error) { return "x", nil })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := mem.Stats().Entries; got != 0 {
+		t.Errorf("Entries=%d, want 0: the remembered miss must be dropped", got)
+	}
+}
+
+func TestWithCacheKeyMetadata(t *testing.T) {
+	orig := map[string]any{"a": 1}
+	out, err := withCacheKeyMetadata(orig, "k")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := orig[cacheMetadataKey]; ok {
+		t.Error("caller metadata must not be modified")
+	}
+	if m, ok := out.(map[string]any); !ok || m[cacheMetadataKey] != "k" || m["a"] != 1 {
+		t.Errorf("out=%#v", out)
+	}
+
+	if _, err := withCacheKeyMetadata([]string{"x"}, "k"); err == nil {
+		t.Error("expected error for unsupported metadata type")
+	}
+}
@@ -0,0 +1,133 @@
+package langfuse
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// cacheKeyVersion prefixes every derived cache key. Bump it whenever the
+// canonical form below changes, so old and new keys never collide.
+const cacheKeyVersion = "v1"
+
+// CacheKeyParams describes an LLM call for BuildCacheKey. Two calls with the
+// same params produce the same key, whichever team or service builds it.
+type CacheKeyParams struct {
+	// Namespace separates otherwise identical calls, e.g. per tenant or per
+	// evaluation suite.
+	Namespace string
+	// Name is the observation name. It is not part of the key; it only scopes
+	// lookups made with LookupOptions.
+	Name string
+	// Model is the model name as sent to the provider.
+	Model string
+	// Messages is the model input: []model.PromptMessage, a slice of
+	// role/content maps, a plain string, or any JSON-encodable value.
+	Messages any
+	// Parameters holds model parameters such as temperature or max tokens.
+	// Map keys are sorted, so insertion order does not matter.
+	Parameters any
+	// PromptName and PromptVersion identify the managed prompt the messages
+	// were compiled from, if any.
+	PromptName    string
+	PromptVersion int
+}
+
+// cacheKeyEnvelope is the canonical document that is hashed. Field order is
+// fixed by the struct; nested maps are sorted by encoding/json.
+type cacheKeyEnvelope struct {
+	Namespace     string          `json:"namespace"`
+	Model         string          `json:"model"`
+	Messages      json.RawMessage `json:"messages"`
+	Parameters    json.RawMessage `json:"parameters"`
+	PromptName    string          `json:"promptName"`
+	PromptVersion int             `json:"promptVersion"`
+}
+
+// BuildCacheKey derives a stable, versioned cache key from p. The key has the
+// form "v1:<sha256 hex>" and is meant to be stored in the generation metadata
+// (see CachedGenerate) and passed to FindCachedGeneration.
+//
+// Messages and Parameters are canonicalized through JSON: structs and maps
+// with the same content hash alike, map keys are sorted, and nil, empty maps
+// and empty slices are equivalent.
+func BuildCacheKey(p CacheKeyParams) (string, error) {
+	messages, err := canonicalJSON(p.Messages)
+	if err != nil {
+		return "", fmt.Errorf("cache key messages: %w", err)
+	}
+
+	parameters, err := canonicalJSON(p.Parameters)
+	if err != nil {
+		return "", fmt.Errorf("cache key parameters: %w", err)
+	}
+
+	envelope, err := json.Marshal(cacheKeyEnvelope{
+		Namespace:     p.Namespace,
+		Model:         p.Model,
+		Messages:      messages,
+		Parameters:    parameters,
+		PromptName:    p.PromptName,
+		PromptVersion: p.PromptVersion,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cache key: %w", err)
+	}
+
+	sum := sha256.Sum256(envelope)
+	return cacheKeyVersion + ":" + hex.EncodeToString(sum[:]), nil
+}
+
+// LookupOptions returns the GenerationCacheOptions scoping a lookup to p.Name,
+// for use with FindCachedGeneration or CachedGenerateOptions.CacheOptions.
+// The other params are already part of the key.
+func (p CacheKeyParams) LookupOptions() *GenerationCacheOptions {
+	return &GenerationCacheOptions{Name: p.Name}
+}
+
+// canonicalJSON encodes value, decodes it into generic maps and slices and
+// encodes it again, so struct field order, map insertion order and number
+// formatting no longer matter.
+func canonicalJSON(value any) (json.RawMessage, error) {
+	if isEmptyValue(value) {
+		return json.RawMessage("null"), nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var generic any
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+
+	if isEmptyValue(generic) {
+		return json.RawMessage("null"), nil
+	}
+
+	return json.Marshal(generic)
+}
+
+func isEmptyValue(value any) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
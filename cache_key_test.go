@@ -0,0 +1,124 @@
+package langfuse
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+func mustBuildCacheKey(t *testing.T, p CacheKeyParams) string {
+	t.Helper()
+	key, err := BuildCacheKey(p)
+	if err != nil {
+		t.Fatalf("BuildCacheKey: %v", err)
+	}
+	return key
+}
+
+func TestBuildCacheKey_Format(t *testing.T) {
+	key := mustBuildCacheKey(t, CacheKeyParams{Model: "gpt-4o"})
+	if !strings.HasPrefix(key, cacheKeyVersion+":") {
+		t.Errorf("key=%q, want %s: prefix", key, cacheKeyVersion)
+	}
+	if len(key) != len(cacheKeyVersion)+1+64 {
+		t.Errorf("len(key)=%d, want version prefix plus 64 hex chars", len(key))
+	}
+}
+
+// TestBuildCacheKey_MessageShapesAgree verifies typed messages and raw maps with
+// the same content produce the same key.
+func TestBuildCacheKey_MessageShapesAgree(t *testing.T) {
+	typed := mustBuildCacheKey(t, CacheKeyParams{
+		Model: "gpt-4o",
+		Messages: []model.PromptMessage{
+			{Role: "system", Content: "be brief"},
+			{Role: "user", Content: "hi"},
+		},
+	})
+	raw := mustBuildCacheKey(t, CacheKeyParams{
+		Model: "gpt-4o",
+		Messages: []map[string]any{
+			{"content": "be brief", "role": "system"},
+			{"role": "user", "content": "hi"},
+		},
+	})
+	m := mustBuildCacheKey(t, CacheKeyParams{
+		Model: "gpt-4o",
+		Messages: []model.M{
+			{"role": "system", "content": "be brief"},
+			{"role": "user", "content": "hi"},
+		},
+	})
+
+	if typed != raw || typed != m {
+		t.Errorf("keys differ: typed=%s raw=%s model.M=%s", typed, raw, m)
+	}
+}
+
+func TestBuildCacheKey_ParametersCanonical(t *testing.T) {
+	a := mustBuildCacheKey(t, CacheKeyParams{
+		Model:      "gpt-4o",
+		Parameters: map[string]any{"temperature": 0.7, "max_tokens": 256},
+	})
+	b := mustBuildCacheKey(t, CacheKeyParams{
+		Model:      "gpt-4o",
+		Parameters: model.M{"max_tokens": 256.0, "temperature": 0.70},
+	})
+	if a != b {
+		t.Errorf("parameter order or number formatting changed the key: %s vs %s", a, b)
+	}
+
+	none := mustBuildCacheKey(t, CacheKeyParams{Model: "gpt-4o"})
+	empty := mustBuildCacheKey(t, CacheKeyParams{Model: "gpt-4o", Parameters: map[string]any{}})
+	if none != empty {
+		t.Errorf("nil and empty parameters should agree: %s vs %s", none, empty)
+	}
+}
+
+func TestBuildCacheKey_Distinguishes(t *testing.T) {
+	base := CacheKeyParams{
+		Namespace:     "ns",
+		Model:         "gpt-4o",
+		Messages:      []model.PromptMessage{{Role: "user", Content: "hi"}},
+		Parameters:    map[string]any{"temperature": 0},
+		PromptName:    "greet",
+		PromptVersion: 1,
+	}
+	baseKey := mustBuildCacheKey(t, base)
+
+	variants := map[string]func(p *CacheKeyParams){
+		"namespace":     func(p *CacheKeyParams) { p.Namespace = "other" },
+		"model":         func(p *CacheKeyParams) { p.Model = "gpt-4o-mini" },
+		"messages":      func(p *CacheKeyParams) { p.Messages = []model.PromptMessage{{Role: "user", Content: "hello"}} },
+		"parameters":    func(p *CacheKeyParams) { p.Parameters = map[string]any{"temperature": 1} },
+		"promptName":    func(p *CacheKeyParams) { p.PromptName = "other" },
+		"promptVersion": func(p *CacheKeyParams) { p.PromptVersion = 2 },
+	}
+	for name, mutate := range variants {
+		p := base
+		mutate(&p)
+		if mustBuildCacheKey(t, p) == baseKey {
+			t.Errorf("changing %s did not change the key", name)
+		}
+	}
+
+	p := base
+	p.Name = "another-function"
+	if mustBuildCacheKey(t, p) != baseKey {
+		t.Error("Name must not be part of the key")
+	}
+}
+
+func TestBuildCacheKey_UnencodableInput(t *testing.T) {
+	if _, err := BuildCacheKey(CacheKeyParams{Messages: make(chan int)}); err == nil {
+		t.Fatal("expected error for unencodable messages")
+	}
+}
+
+func TestCacheKeyParams_LookupOptions(t *testing.T) {
+	opts := CacheKeyParams{Name: "summarize", Model: "gpt-4o", PromptName: "sum", PromptVersion: 4}.LookupOptions()
+	if *opts != (GenerationCacheOptions{Name: "summarize"}) {
+		t.Errorf("options=%+v, want only the name scope; the key covers the rest", opts)
+	}
+}
@@ -0,0 +1,373 @@
+package langfuse
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+const (
+	defaultMemoryCacheMaxEntries  = 1024
+	defaultMemoryCacheTTL         = 10 * time.Minute
+	defaultMemoryCacheNegativeTTL = 30 * time.Second
+)
+
+// MemoryCacheOptions configures a MemoryGenerationCache. Zero values select the
+// defaults.
+type MemoryCacheOptions struct {
+	// MaxEntries bounds the number of remembered lookups (hits and misses).
+	// The least recently used entry is evicted first.
+	MaxEntries int
+	// TTL is how long a hit is served from memory.
+	TTL time.Duration
+	// NegativeTTL is how long a miss is remembered. Keep it short so a
+	// generation recorded by another process is picked up quickly. A negative
+	// value disables negative caching.
+	NegativeTTL time.Duration
+}
+
+// MemoryCacheStats is a snapshot of MemoryGenerationCache counters.
+type MemoryCacheStats struct {
+	// Hits counts lookups answered with a cached observation.
+	Hits uint64
+	// NegativeHits counts lookups answered with a remembered miss.
+	NegativeHits uint64
+	// Misses counts lookups that had to go to the underlying cache.
+	Misses uint64
+	// Coalesced counts lookups that waited on an identical in-flight lookup
+	// instead of issuing their own.
+	Coalesced uint64
+	// Evictions counts entries dropped to stay within MaxEntries.
+	Evictions uint64
+	// Entries is the number of entries currently held.
+	Entries int
+}
+
+// MemoryGenerationCache is an in-process GenerationCache that sits in front of
+// another GenerationCache (usually *Langfuse). Hits are kept in a size-bounded
+// LRU with a TTL, misses are remembered for NegativeTTL, and concurrent lookups
+// of the same key share a single underlying request.
+//
+// Returned observations are shared between callers and must be treated as
+// read-only.
+type MemoryGenerationCache struct {
+	next        GenerationCache
+	maxEntries  int
+	ttl         time.Duration
+	negativeTTL time.Duration
+	now         func() time.Time
+
+	mu       sync.Mutex
+	lru      *list.List
+	entries  map[string]*list.Element
+	inflight map[string]*memoryCacheCall
+	stats    MemoryCacheStats
+}
+
+type memoryCacheEntry struct {
+	key       string
+	cacheKey  string
+	obs       *model.ObservationView
+	expiresAt time.Time
+}
+
+type memoryCacheCall struct {
+	done chan struct{}
+	obs  *model.ObservationView
+	err  error
+}
+
+var _ GenerationCache = (*MemoryGenerationCache)(nil)
+
+// NewMemoryGenerationCache wraps next with an in-process cache layer.
+func NewMemoryGenerationCache(next GenerationCache, options *MemoryCacheOptions) *MemoryGenerationCache {
+	c := &MemoryGenerationCache{
+		next:        next,
+		maxEntries:  defaultMemoryCacheMaxEntries,
+		ttl:         defaultMemoryCacheTTL,
+		negativeTTL: defaultMemoryCacheNegativeTTL,
+		now:         time.Now,
+		lru:         list.New(),
+		entries:     make(map[string]*list.Element),
+		inflight:    make(map[string]*memoryCacheCall),
+	}
+
+	if options != nil {
+		if options.MaxEntries > 0 {
+			c.maxEntries = options.MaxEntries
+		}
+		if options.TTL > 0 {
+			c.ttl = options.TTL
+		}
+		if options.NegativeTTL != 0 {
+			c.negativeTTL = options.NegativeTTL
+		}
+	}
+
+	return c
+}
+
+// FindCachedGeneration implements GenerationCache. A lookup waiting on an
+// identical in-flight lookup that was cancelled or timed out by its own caller
+// retries with ctx instead of sharing that failure.
+func (c *MemoryGenerationCache) FindCachedGeneration(ctx context.Context, cacheKey string, options *GenerationCacheOptions) (*model.ObservationView, error) {
+	if cacheKey == "" {
+		return nil, fmt.Errorf("cache key is required")
+	}
+
+	key, err := memoryCacheKey(cacheKey, options)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		c.mu.Lock()
+		if obs, ok := c.getLocked(key); ok {
+			c.mu.Unlock()
+			return obs, nil
+		}
+
+		call, ok := c.inflight[key]
+		if !ok {
+			call = &memoryCacheCall{done: make(chan struct{})}
+			c.inflight[key] = call
+			c.stats.Misses++
+			c.mu.Unlock()
+
+			return c.lookup(ctx, key, cacheKey, options, call)
+		}
+		c.stats.Coalesced++
+		c.mu.Unlock()
+
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if errors.Is(call.err, context.Canceled) || errors.Is(call.err, context.DeadlineExceeded) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		return call.obs, call.err
+	}
+}
+
+// lookup asks the underlying cache on behalf of call and releases its
+// waiters, even when the underlying cache panics.
+func (c *MemoryGenerationCache) lookup(ctx context.Context, key, cacheKey string, options *GenerationCacheOptions, call *memoryCacheCall) (*model.ObservationView, error) {
+	returned := false
+	defer func() {
+		if !returned {
+			call.obs, call.err = nil, fmt.Errorf("cache lookup panicked")
+		}
+
+		c.mu.Lock()
+		delete(c.inflight, key)
+		if call.err == nil {
+			c.setLocked(key, cacheKey, call.obs, options)
+		}
+		c.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.obs, call.err = c.next.FindCachedGeneration(ctx, cacheKey, options)
+	returned = true
+
+	return call.obs, call.err
+}
+
+// FindCachedGenerationBatch implements GenerationCache. Keys answered from
+// memory are not sent to the underlying cache; the rest are looked up in one
+// batch call. Batch lookups are not coalesced with in-flight single lookups.
+func (c *MemoryGenerationCache) FindCachedGenerationBatch(ctx context.Context, cacheKeys []string, options *GenerationCacheOptions) (map[string]*model.ObservationView, error) {
+	result := make(map[string]*model.ObservationView)
+	if len(cacheKeys) == 0 {
+		return result, nil
+	}
+
+	keys := make(map[string]string, len(cacheKeys))
+	var remaining []string
+
+	c.mu.Lock()
+	for _, cacheKey := range cacheKeys {
+		if cacheKey == "" {
+			c.mu.Unlock()
+			return nil, fmt.Errorf("cache key is required")
+		}
+		if _, ok := keys[cacheKey]; ok {
+			continue
+		}
+
+		key, err := memoryCacheKey(cacheKey, options)
+		if err != nil {
+			c.mu.Unlock()
+			return nil, err
+		}
+		keys[cacheKey] = key
+
+		if obs, ok := c.getLocked(key); ok {
+			if obs != nil {
+				result[cacheKey] = obs
+			}
+			continue
+		}
+
+		c.stats.Misses++
+		remaining = append(remaining, cacheKey)
+	}
+	c.mu.Unlock()
+
+	if len(remaining) == 0 {
+		return result, nil
+	}
+
+	found, err := c.next.FindCachedGenerationBatch(ctx, remaining, options)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, cacheKey := range remaining {
+		obs := found[cacheKey]
+		c.setLocked(keys[cacheKey], cacheKey, obs, options)
+		if obs != nil {
+			result[cacheKey] = obs
+		}
+	}
+
+	return result, nil
+}
+
+// Invalidate drops every remembered hit or miss for cacheKey, whatever lookup
+// options it was stored under. Call it after recording a new generation for a
+// key that may have been remembered as a miss.
+func (c *MemoryGenerationCache) Invalidate(cacheKey string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for e := c.lru.Front(); e != nil; {
+		next := e.Next()
+		if entry, ok := e.Value.(*memoryCacheEntry); ok && entry.cacheKey == cacheKey {
+			c.removeLocked(e)
+		}
+		e = next
+	}
+}
+
+// Purge drops every entry. Counters are kept.
+func (c *MemoryGenerationCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lru.Init()
+	c.entries = make(map[string]*list.Element)
+}
+
+// Stats returns a snapshot of the cache counters.
+func (c *MemoryGenerationCache) Stats() MemoryCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = c.lru.Len()
+	return stats
+}
+
+// getLocked returns the remembered result for key. The observation is nil for
+// a remembered miss. Expired entries are dropped. c.mu must be held.
+func (c *MemoryGenerationCache) getLocked(key string) (*model.ObservationView, bool) {
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry, ok := e.Value.(*memoryCacheEntry)
+	if !ok || !c.now().Before(entry.expiresAt) {
+		c.removeLocked(e)
+		return nil, false
+	}
+
+	c.lru.MoveToFront(e)
+	if entry.obs == nil {
+		c.stats.NegativeHits++
+	} else {
+		c.stats.Hits++
+	}
+
+	return entry.obs, true
+}
+
+// setLocked stores a hit (obs != nil) or a miss (obs == nil). A hit expires
+// no later than options.MaxAge after the generation started, so it is not
+// served once the underlying cache would reject it. c.mu must be held.
+func (c *MemoryGenerationCache) setLocked(key, cacheKey string, obs *model.ObservationView, options *GenerationCacheOptions) {
+	ttl := c.ttl
+	if obs == nil {
+		if c.negativeTTL < 0 {
+			return
+		}
+		ttl = c.negativeTTL
+	}
+
+	expiresAt := c.now().Add(ttl)
+	if obs != nil && options != nil && options.MaxAge > 0 && obs.StartTime != nil {
+		if staleAt := obs.StartTime.Add(options.MaxAge); staleAt.Before(expiresAt) {
+			expiresAt = staleAt
+		}
+		if !c.now().Before(expiresAt) {
+			return
+		}
+	}
+
+	entry := &memoryCacheEntry{
+		key:       key,
+		cacheKey:  cacheKey,
+		obs:       obs,
+		expiresAt: expiresAt,
+	}
+
+	if e, ok := c.entries[key]; ok {
+		e.Value = entry
+		c.lru.MoveToFront(e)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(entry)
+
+	for c.lru.Len() > c.maxEntries {
+		c.removeLocked(c.lru.Back())
+		c.stats.Evictions++
+	}
+}
+
+func (c *MemoryGenerationCache) removeLocked(e *list.Element) {
+	if entry, ok := e.Value.(*memoryCacheEntry); ok {
+		delete(c.entries, entry.key)
+	}
+	c.lru.Remove(e)
+}
+
+// memoryCacheKey scopes a cache key by its lookup options, so the same key
+// looked up under different options is remembered separately.
+func memoryCacheKey(cacheKey string, options *GenerationCacheOptions) (string, error) {
+	if options == nil {
+		return cacheKey, nil
+	}
+
+	scope, err := json.Marshal(options)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cache options: %w", err)
+	}
+
+	return cacheKey + "\x00" + string(scope), nil
+}
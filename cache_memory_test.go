@@ -0,0 +1,396 @@
+package langfuse
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+// fakeGenerationCache is an in-memory GenerationCache that counts calls.
+type fakeGenerationCache struct {
+	mu      sync.Mutex
+	data    map[string]*model.ObservationView
+	calls   atomic.Int64
+	batches atomic.Int64
+	block   chan struct{}
+	err     error
+}
+
+func (f *fakeGenerationCache) FindCachedGeneration(ctx context.Context, cacheKey string, _ *GenerationCacheOptions) (*model.ObservationView, error) {
+	f.calls.Add(1)
+	if f.block != nil {
+		<-f.block
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.data[cacheKey], nil
+}
+
+func (f *fakeGenerationCache) FindCachedGenerationBatch(ctx context.Context, cacheKeys []string, _ *GenerationCacheOptions) (map[string]*model.ObservationView, error) {
+	f.batches.Add(1)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result := make(map[string]*model.ObservationView)
+	for _, k := range cacheKeys {
+		if obs, ok := f.data[k]; ok {
+			result[k] = obs
+		}
+	}
+	return result, nil
+}
+
+func TestMemoryGenerationCache_HitAndTTL(t *testing.T) {
+	next := &fakeGenerationCache{data: map[string]*model.ObservationView{"k": {ID: "obs-1"}}}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewMemoryGenerationCache(next, &MemoryCacheOptions{TTL: time.Minute})
+	c.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		obs, err := c.FindCachedGeneration(context.Background(), "k", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if obs == nil || obs.ID != "obs-1" {
+			t.Fatalf("obs=%+v, want obs-1", obs)
+		}
+	}
+	if got := next.calls.Load(); got != 1 {
+		t.Errorf("underlying calls=%d, want 1", got)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := c.FindCachedGeneration(context.Background(), "k", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := next.calls.Load(); got != 2 {
+		t.Errorf("underlying calls after TTL=%d, want 2", got)
+	}
+
+	stats := c.Stats()
+	if stats.Hits != 2 || stats.Misses != 2 {
+		t.Errorf("stats=%+v, want 2 hits and 2 misses", stats)
+	}
+}
+
+func TestMemoryGenerationCache_NegativeEntries(t *testing.T) {
+	next := &fakeGenerationCache{data: map[string]*model.ObservationView{}}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewMemoryGenerationCache(next, &MemoryCacheOptions{NegativeTTL: 5 * time.Second})
+	c.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		obs, err := c.FindCachedGeneration(context.Background(), "missing", nil)
+		if err != nil || obs != nil {
+			t.Fatalf("obs=%+v err=%v, want miss", obs, err)
+		}
+	}
+	if got := next.calls.Load(); got != 1 {
+		t.Errorf("underlying calls=%d, want 1 (second miss from memory)", got)
+	}
+	if got := c.Stats().NegativeHits; got != 1 {
+		t.Errorf("NegativeHits=%d, want 1", got)
+	}
+
+	next.mu.Lock()
+	next.data["missing"] = &model.ObservationView{ID: "obs-new"}
+	next.mu.Unlock()
+	c.Invalidate("missing")
+
+	obs, err := c.FindCachedGeneration(context.Background(), "missing", nil)
+	if err != nil || obs == nil || obs.ID != "obs-new" {
+		t.Fatalf("obs=%+v err=%v, want obs-new after Invalidate", obs, err)
+	}
+}
+
+func TestMemoryGenerationCache_NegativeCachingDisabled(t *testing.T) {
+	next := &fakeGenerationCache{data: map[string]*model.ObservationView{}}
+	c := NewMemoryGenerationCache(next, &MemoryCacheOptions{NegativeTTL: -1})
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.FindCachedGeneration(context.Background(), "missing", nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got := next.calls.Load(); got != 2 {
+		t.Errorf("underlying calls=%d, want 2", got)
+	}
+}
+
+func TestMemoryGenerationCache_Eviction(t *testing.T) {
+	next := &fakeGenerationCache{data: map[string]*model.ObservationView{
+		"a": {ID: "a"}, "b": {ID: "b"}, "c": {ID: "c"},
+	}}
+	c := NewMemoryGenerationCache(next, &MemoryCacheOptions{MaxEntries: 2})
+	ctx := context.Background()
+
+	_, _ = c.FindCachedGeneration(ctx, "a", nil)
+	_, _ = c.FindCachedGeneration(ctx, "b", nil)
+	_, _ = c.FindCachedGeneration(ctx, "a", nil) // a is now most recent
+	_, _ = c.FindCachedGeneration(ctx, "c", nil) // evicts b
+
+	stats := c.Stats()
+	if stats.Evictions != 1 || stats.Entries != 2 {
+		t.Errorf("stats=%+v, want 1 eviction and 2 entries", stats)
+	}
+
+	before := next.calls.Load()
+	_, _ = c.FindCachedGeneration(ctx, "a", nil)
+	if next.calls.Load() != before {
+		t.Error("a should still be cached")
+	}
+	_, _ = c.FindCachedGeneration(ctx, "b", nil)
+	if next.calls.Load() != before+1 {
+		t.Error("b should have been evicted")
+	}
+}
+
+func TestMemoryGenerationCache_OptionsScopeEntries(t *testing.T) {
+	next := &fakeGenerationCache{data: map[string]*model.ObservationView{"k": {ID: "obs"}}}
+	c := NewMemoryGenerationCache(next, nil)
+	ctx := context.Background()
+
+	_, _ = c.FindCachedGeneration(ctx, "k", &GenerationCacheOptions{Name: "a"})
+	_, _ = c.FindCachedGeneration(ctx, "k", &GenerationCacheOptions{Name: "b"})
+	_, _ = c.FindCachedGeneration(ctx, "k", &GenerationCacheOptions{Name: "a"})
+
+	if got := next.calls.Load(); got != 2 {
+		t.Errorf("underlying calls=%d, want 2 (one per distinct options)", got)
+	}
+}
+
+func TestMemoryGenerationCache_CoalescesConcurrentLookups(t *testing.T) {
+	next := &fakeGenerationCache{
+		data:  map[string]*model.ObservationView{"k": {ID: "obs"}},
+		block: make(chan struct{}),
+	}
+	c := NewMemoryGenerationCache(next, nil)
+
+	const callers = 10
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			obs, err := c.FindCachedGeneration(context.Background(), "k", nil)
+			if err != nil || obs == nil {
+				t.Errorf("obs=%+v err=%v", obs, err)
+			}
+		}()
+	}
+
+	// Wait until every caller is either in flight or waiting on it.
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		stats := c.Stats()
+		if stats.Misses+stats.Coalesced == callers {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("callers did not converge: %+v", stats)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(next.block)
+	wg.Wait()
+
+	if got := next.calls.Load(); got != 1 {
+		t.Errorf("underlying calls=%d, want 1", got)
+	}
+	if got := c.Stats().Coalesced; got != callers-1 {
+		t.Errorf("Coalesced=%d, want %d", got, callers-1)
+	}
+}
+
+func TestMemoryGenerationCache_ErrorsNotCached(t *testing.T) {
+	next := &fakeGenerationCache{err: errors.New("boom")}
+	c := NewMemoryGenerationCache(next, nil)
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.FindCachedGeneration(context.Background(), "k", nil); err == nil {
+			t.Fatal("expected error")
+		}
+	}
+	if got := next.calls.Load(); got != 2 {
+		t.Errorf("underlying calls=%d, want 2", got)
+	}
+}
+
+func TestMemoryGenerationCache_Batch(t *testing.T) {
+	next := &fakeGenerationCache{data: map[string]*model.ObservationView{"a": {ID: "a"}}}
+	c := NewMemoryGenerationCache(next, nil)
+	ctx := context.Background()
+
+	if _, err := c.FindCachedGeneration(ctx, "a", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	hits, err := c.FindCachedGenerationBatch(ctx, []string{"a", "b"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hits) != 1 || hits["a"] == nil {
+		t.Fatalf("hits=%+v, want only a", hits)
+	}
+	if got := next.batches.Load(); got != 1 {
+		t.Errorf("batches=%d, want 1", got)
+	}
+
+	// Both keys are now remembered: a as a hit, b as a miss.
+	if _, err := c.FindCachedGenerationBatch(ctx, []string{"a", "b"}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := next.batches.Load(); got != 1 {
+		t.Errorf("batches=%d, want 1 (second batch served from memory)", got)
+	}
+}
+
+func TestMemoryGenerationCache_MaxAgeCapsTTL(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	started := now.Add(-50 * time.Second)
+	next := &fakeGenerationCache{data: map[string]*model.ObservationView{"k": {ID: "obs-1", StartTime: &started}}}
+	c := NewMemoryGenerationCache(next, &MemoryCacheOptions{TTL: 10 * time.Minute})
+	c.now = func() time.Time { return now }
+	options := &GenerationCacheOptions{MaxAge: time.Minute}
+	ctx := context.Background()
+
+	for _, find := range []func() (*model.ObservationView, error){
+		func() (*model.ObservationView, error) { return c.FindCachedGeneration(ctx, "k", options) },
+		func() (*model.ObservationView, error) {
+			hits, err := c.FindCachedGenerationBatch(ctx, []string{"k"}, options)
+			return hits["k"], err
+		},
+	} {
+		now = started.Add(50 * time.Second)
+		c.Invalidate("k")
+		before := next.calls.Load() + next.batches.Load()
+
+		for range 2 {
+			if obs, err := find(); err != nil || obs == nil {
+				t.Fatalf("obs=%v err=%v, want a hit", obs, err)
+			}
+		}
+		if got := next.calls.Load() + next.batches.Load() - before; got != 1 {
+			t.Errorf("underlying lookups=%d, want 1 while fresh", got)
+		}
+
+		// Past MaxAge the entry must not be served from memory.
+		now = started.Add(61 * time.Second)
+		if _, err := find(); err != nil {
+			t.Fatal(err)
+		}
+		if got := next.calls.Load() + next.batches.Load() - before; got != 2 {
+			t.Errorf("underlying lookups=%d, want 2 after MaxAge", got)
+		}
+	}
+}
+
+// funcGenerationCache answers single lookups with its function.
+type funcGenerationCache func(ctx context.Context) (*model.ObservationView, error)
+
+func (f funcGenerationCache) FindCachedGeneration(ctx context.Context, _ string, _ *GenerationCacheOptions) (*model.ObservationView, error) {
+	return f(ctx)
+}
+
+func (f funcGenerationCache) FindCachedGenerationBatch(context.Context, []string, *GenerationCacheOptions) (map[string]*model.ObservationView, error) {
+	return nil, errors.New("not implemented")
+}
+
+// waitCoalesced waits until n lookups of c are waiting on an in-flight one.
+func waitCoalesced(t *testing.T, c *MemoryGenerationCache, n uint64) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for c.Stats().Coalesced < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("lookups did not coalesce: %+v", c.Stats())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestMemoryGenerationCache_WaiterRetriesAfterLeaderCancelled(t *testing.T) {
+	var calls atomic.Int64
+	started := make(chan struct{})
+	c := NewMemoryGenerationCache(funcGenerationCache(func(ctx context.Context) (*model.ObservationView, error) {
+		if calls.Add(1) == 1 {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return &model.ObservationView{ID: "obs"}, nil
+	}), nil)
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := c.FindCachedGeneration(leaderCtx, "k", nil)
+		leaderErr <- err
+	}()
+	<-started
+
+	waiter := make(chan error, 1)
+	go func() {
+		obs, err := c.FindCachedGeneration(context.Background(), "k", nil)
+		if err == nil && obs == nil {
+			err = errors.New("no observation")
+		}
+		waiter <- err
+	}()
+	waitCoalesced(t, c, 1)
+	cancel()
+
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("leader err=%v, want context.Canceled", err)
+	}
+	if err := <-waiter; err != nil {
+		t.Errorf("waiter err=%v, want a retried lookup", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("underlying calls=%d, want 2", got)
+	}
+}
+
+func TestMemoryGenerationCache_LeaderPanicReleasesWaiters(t *testing.T) {
+	release := make(chan struct{})
+	c := NewMemoryGenerationCache(funcGenerationCache(func(context.Context) (*model.ObservationView, error) {
+		<-release
+		panic("broken cache")
+	}), nil)
+
+	go func() {
+		defer func() { _ = recover() }()
+		_, _ = c.FindCachedGeneration(context.Background(), "k", nil)
+	}()
+	// The leader registers before the waiter below can coalesce with it.
+	deadline := time.Now().Add(2 * time.Second)
+	for c.Stats().Misses == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("leader did not start")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	waiter := make(chan error, 1)
+	go func() {
+		_, err := c.FindCachedGeneration(context.Background(), "k", nil)
+		waiter <- err
+	}()
+	waitCoalesced(t, c, 1)
+	close(release)
+
+	select {
+	case err := <-waiter:
+		if err == nil {
+			t.Error("waiter must see the failed lookup")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("waiter blocked after the leader panicked")
+	}
+}
@@ -3,11 +3,16 @@ package langfuse
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/ezardev-team/langfuse-go/model"
 )
 
 // TestFindCachedGeneration_HitsV2Endpoint verifies the cache lookup uses the v2
@@ -267,3 +272,329 @@ func TestFindCachedGeneration_EmptyKey(t *testing.T) {
 		t.Fatal("expected error for empty cache key, got nil")
 	}
 }
+
+// TestFindCachedGenerationBatch_SingleAnyOfRequest verifies a batch is resolved
+// with one observations request carrying an any-of metadata filter, and that
+// the exact-match check still rejects near misses and accepts quoted values.
+func TestFindCachedGenerationBatch_SingleAnyOfRequest(t *testing.T) {
+	var requests int
+	var capturedFilter string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		capturedFilter = r.URL.Query().Get("filter")
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": []map[string]any{
+				{"id": "obs-a", "type": "GENERATION", "metadata": map[string]any{cacheMetadataKey: "key-a"}},
+				{"id": "obs-b", "type": "GENERATION", "metadata": map[string]any{cacheMetadataKey: `"key-b"`}},
+				{"id": "obs-a-older", "type": "GENERATION", "metadata": map[string]any{cacheMetadataKey: "key-a"}},
+				{"id": "obs-x", "type": "GENERATION", "metadata": map[string]any{cacheMetadataKey: "key-x"}},
+			},
+			"meta": map[string]any{"cursor": nil},
+		})
+	}))
+	defer srv.Close()
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	hits, err := l.FindCachedGenerationBatch(context.Background(), []string{"key-a", "key-b", "key-c", "key-a"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if requests != 1 {
+		t.Errorf("requests=%d, want 1", requests)
+	}
+	if len(hits) != 2 {
+		t.Fatalf("len(hits)=%d, want 2: %+v", len(hits), hits)
+	}
+	if hits["key-a"] == nil || hits["key-a"].ID != "obs-a" {
+		t.Errorf("key-a=%+v, want first match obs-a", hits["key-a"])
+	}
+	if hits["key-b"] == nil || hits["key-b"].ID != "obs-b" {
+		t.Errorf("key-b=%+v, want obs-b", hits["key-b"])
+	}
+	if _, ok := hits["key-x"]; ok {
+		t.Error("key-x was not requested and must not be returned")
+	}
+
+	var conds []map[string]any
+	if err := json.Unmarshal([]byte(capturedFilter), &conds); err != nil {
+		t.Fatalf("filter not JSON: %v (raw=%s)", err, capturedFilter)
+	}
+	var metaCond map[string]any
+	for _, c := range conds {
+		if c["column"] == "metadata" {
+			metaCond = c
+		}
+	}
+	if metaCond == nil || metaCond["operator"] != "any of" || metaCond["key"] != cacheMetadataKey {
+		t.Fatalf("expected any-of metadata filter, got: %+v", conds)
+	}
+	values, _ := metaCond["value"].([]any)
+	if len(values) != 6 {
+		t.Errorf("any-of values=%v, want raw and quoted form of 3 distinct keys", values)
+	}
+}
+
+// TestFindCachedGenerationBatch_ChunksAndPages verifies large batches are split
+// into chunks and that a chunk follows the cursor until all keys are found.
+func TestFindCachedGenerationBatch_ChunksAndPages(t *testing.T) {
+	var mu sync.Mutex
+	var cursors []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		cursors = append(cursors, r.URL.Query().Get("cursor"))
+		mu.Unlock()
+
+		data := []map[string]any{}
+		var next any
+		if r.URL.Query().Get("cursor") == "" {
+			next = "page-2"
+		} else {
+			data = append(data, map[string]any{
+				"id":       "obs-late",
+				"type":     "GENERATION",
+				"metadata": map[string]any{cacheMetadataKey: "key-0"},
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": data,
+			"meta": map[string]any{"cursor": next},
+		})
+	}))
+	defer srv.Close()
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	keys := make([]string, cacheBatchChunkSize+1)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+	}
+
+	hits, err := l.FindCachedGenerationBatch(context.Background(), keys, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hits["key-0"] == nil || hits["key-0"].ID != "obs-late" {
+		t.Errorf("key-0=%+v, want obs-late from second page", hits["key-0"])
+	}
+
+	// Two chunks, each walking two pages.
+	if len(cursors) != 4 {
+		t.Errorf("requests=%d (cursors=%v), want 4", len(cursors), cursors)
+	}
+}
+
+// TestFindCachedGenerationBatch_FallbackOnRejectedFilter verifies a 400 for the
+// any-of filter falls back to per-key lookups instead of failing the batch.
+func TestFindCachedGenerationBatch_FallbackOnRejectedFilter(t *testing.T) {
+	var mu sync.Mutex
+	perKey := map[string]int{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var conds []map[string]any
+		_ = json.Unmarshal([]byte(r.URL.Query().Get("filter")), &conds)
+
+		var metaCond map[string]any
+		for _, c := range conds {
+			if c["column"] == "metadata" {
+				metaCond = c
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if metaCond["operator"] == "any of" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message":"invalid filter"}`))
+			return
+		}
+
+		key, _ := metaCond["value"].(string)
+		mu.Lock()
+		perKey[key]++
+		mu.Unlock()
+
+		data := []map[string]any{}
+		if key == "key-hit" {
+			data = append(data, map[string]any{
+				"id":       "obs-hit",
+				"type":     "GENERATION",
+				"metadata": map[string]any{cacheMetadataKey: key},
+			})
+		}
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": data,
+			"meta": map[string]any{"cursor": nil},
+		})
+	}))
+	defer srv.Close()
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	hits, err := l.FindCachedGenerationBatch(context.Background(), []string{"key-hit", "key-miss"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hits) != 1 || hits["key-hit"] == nil {
+		t.Fatalf("hits=%+v, want only key-hit", hits)
+	}
+	if perKey["key-hit"] != 1 || perKey["key-miss"] != 1 {
+		t.Errorf("per-key lookups=%v, want one per key", perKey)
+	}
+}
+
+// TestFindCachedGenerationBatch_ServerError verifies a non-400 failure is
+// surfaced rather than masked by the per-key fallback.
+func TestFindCachedGenerationBatch_ServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"message":"boom"}`))
+	}))
+	defer srv.Close()
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	if _, err := l.FindCachedGenerationBatch(context.Background(), []string{"key-a"}, nil); err == nil {
+		t.Fatal("expected error for 500 status, got nil")
+	}
+}
+
+func TestFindCachedGenerationBatch_EmptyKey(t *testing.T) {
+	l := &Langfuse{}
+	if _, err := l.FindCachedGenerationBatch(context.Background(), []string{"key-a", ""}, nil); err == nil {
+		t.Fatal("expected error for empty cache key, got nil")
+	}
+}
+
+// TestFindCachedGeneration_ScopingOptions verifies freshness, environment,
+// model, prompt version and level options are sent to the API.
+func TestFindCachedGeneration_ScopingOptions(t *testing.T) {
+	var capturedQuery url.Values
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		capturedQuery = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": []map[string]any{},
+			"meta": map[string]any{"cursor": nil},
+		})
+	}))
+	defer srv.Close()
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	before := time.Now().Add(-time.Hour)
+	_, err := l.FindCachedGeneration(context.Background(), "key-abc", &GenerationCacheOptions{
+		MaxAge:        time.Hour,
+		Environment:   "production",
+		Model:         "gpt-4o",
+		PromptVersion: 3,
+		MinLevel:      model.ObservationLevelDefault,
+		ExcludeErrors: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := capturedQuery["environment"]; len(got) != 1 || got[0] != "production" {
+		t.Errorf("environment=%v, want [production]", got)
+	}
+	from, err := time.Parse(time.RFC3339, capturedQuery.Get("fromStartTime"))
+	if err != nil {
+		t.Fatalf("fromStartTime=%q not RFC3339: %v", capturedQuery.Get("fromStartTime"), err)
+	}
+	if from.Before(before.Add(-time.Second)) || from.After(time.Now().Add(-time.Hour+time.Second)) {
+		t.Errorf("fromStartTime=%v, want about one hour ago", from)
+	}
+
+	var conds []map[string]any
+	if err := json.Unmarshal([]byte(capturedQuery.Get("filter")), &conds); err != nil {
+		t.Fatalf("filter not JSON: %v", err)
+	}
+	byColumn := map[string]map[string]any{}
+	for _, c := range conds {
+		byColumn[c["column"].(string)] = c
+	}
+	if c := byColumn["providedModelName"]; c == nil || c["value"] != "gpt-4o" {
+		t.Errorf("model filter=%v", c)
+	}
+	if c := byColumn["promptVersion"]; c == nil || c["type"] != "number" || c["value"] != float64(3) {
+		t.Errorf("promptVersion filter=%v", c)
+	}
+	c := byColumn["level"]
+	if c == nil || c["operator"] != "any of" {
+		t.Fatalf("level filter=%v", c)
+	}
+	levels, _ := c["value"].([]any)
+	if len(levels) != 2 || levels[0] != "DEFAULT" || levels[1] != "WARNING" {
+		t.Errorf("levels=%v, want [DEFAULT WARNING]", levels)
+	}
+}
+
+// TestFindCachedGeneration_ScopingRechecked verifies out-of-scope generations
+// returned by a lenient server are skipped client-side.
+func TestFindCachedGeneration_ScopingRechecked(t *testing.T) {
+	old := time.Now().Add(-48 * time.Hour)
+	fresh := time.Now().Add(-time.Minute)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": []map[string]any{
+				{"id": "stale", "type": "GENERATION", "startTime": old, "providedModelName": "gpt-4o", "metadata": map[string]any{cacheMetadataKey: "key-abc"}},
+				{"id": "errored", "type": "GENERATION", "startTime": fresh, "providedModelName": "gpt-4o", "level": "ERROR", "metadata": map[string]any{cacheMetadataKey: "key-abc"}},
+				{"id": "other-model", "type": "GENERATION", "startTime": fresh, "providedModelName": "gpt-3.5", "metadata": map[string]any{cacheMetadataKey: "key-abc"}},
+				{"id": "good", "type": "GENERATION", "startTime": fresh, "providedModelName": "gpt-4o", "metadata": map[string]any{cacheMetadataKey: "key-abc"}},
+			},
+			"meta": map[string]any{"cursor": nil},
+		})
+	}))
+	defer srv.Close()
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	obs, err := l.FindCachedGeneration(context.Background(), "key-abc", &GenerationCacheOptions{
+		MaxAge:        24 * time.Hour,
+		Model:         "gpt-4o",
+		ExcludeErrors: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if obs == nil || obs.ID != "good" {
+		t.Fatalf("obs=%+v, want good", obs)
+	}
+}
+
+func TestGenerationCacheOptions_InvalidLevels(t *testing.T) {
+	l := &Langfuse{}
+	ctx := context.Background()
+
+	if _, err := l.FindCachedGeneration(ctx, "k", &GenerationCacheOptions{MinLevel: "LOUD"}); err == nil {
+		t.Error("expected error for unknown MinLevel")
+	}
+	if _, err := l.FindCachedGeneration(ctx, "k", &GenerationCacheOptions{
+		MinLevel:      model.ObservationLevelError,
+		ExcludeErrors: true,
+	}); err == nil {
+		t.Error("expected error when options exclude every level")
+	}
+}
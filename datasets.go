@@ -0,0 +1,163 @@
+package langfuse
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/ezardev-team/langfuse-go/internal/pkg/api"
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+// CreateDatasetRequest describes a new dataset for CreateDataset.
+type CreateDatasetRequest struct {
+	Name        string
+	Description string
+	Metadata    any
+}
+
+// CreateDataset creates a dataset. Dataset names are unique per project.
+func (l *Langfuse) CreateDataset(ctx context.Context, req CreateDatasetRequest) (*model.Dataset, error) {
+	if req.Name == "" {
+		return nil, fmt.Errorf("dataset name is required")
+	}
+
+	apiReq := &api.DatasetCreateRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		Metadata:    req.Metadata,
+	}
+	res := api.DatasetResponse{}
+
+	if err := l.client.CreateDataset(ctx, apiReq, &res); err != nil {
+		return nil, err
+	}
+
+	if !res.IsSuccess() {
+		return nil, newAPIError("create dataset", apiReq, res.Code, res.RawBody)
+	}
+
+	return &res.Dataset, nil
+}
+
+// GetDataset fetches a dataset by name.
+func (l *Langfuse) GetDataset(ctx context.Context, name string) (*model.Dataset, error) {
+	req := api.DatasetRequest{Name: name}
+	res := api.DatasetResponse{}
+
+	if err := l.client.Dataset(ctx, &req, &res); err != nil {
+		return nil, err
+	}
+
+	if !res.IsSuccess() {
+		return nil, newAPIError("dataset", &req, res.Code, res.RawBody)
+	}
+
+	return &res.Dataset, nil
+}
+
+// ListDatasets returns an iterator over all datasets of the project.
+func (l *Langfuse) ListDatasets(ctx context.Context) iter.Seq2[*model.Dataset, error] {
+	return listPages(ctx, func(ctx context.Context, page int) ([]model.Dataset, model.PageMeta, error) {
+		req := api.DatasetsRequest{Page: page}
+		res := api.DatasetsResponse{}
+		if err := l.client.Datasets(ctx, &req, &res); err != nil {
+			return nil, model.PageMeta{}, err
+		}
+		if !res.IsSuccess() {
+			return nil, model.PageMeta{}, newAPIError("datasets", &req, res.Code, res.RawBody)
+		}
+		return res.Data, res.Meta, nil
+	})
+}
+
+// UpsertDatasetItemRequest describes a dataset item for UpsertDatasetItem.
+// Passing the ID of an existing item updates it; an empty ID creates a new
+// item with a generated ID.
+type UpsertDatasetItemRequest struct {
+	ID             string
+	DatasetName    string
+	Input          any
+	ExpectedOutput any
+	Metadata       any
+	// SourceTraceID and SourceObservationID link the item to the production
+	// trace or observation it was captured from.
+	SourceTraceID       string
+	SourceObservationID string
+	// Status archives an item when set to model.DatasetStatusArchived.
+	Status model.DatasetStatus
+}
+
+// UpsertDatasetItem creates or updates a dataset item.
+func (l *Langfuse) UpsertDatasetItem(ctx context.Context, req UpsertDatasetItemRequest) (*model.DatasetItem, error) {
+	if req.DatasetName == "" {
+		return nil, fmt.Errorf("dataset name is required")
+	}
+
+	apiReq := &api.DatasetItemUpsertRequest{
+		ID:                  req.ID,
+		DatasetName:         req.DatasetName,
+		Input:               req.Input,
+		ExpectedOutput:      req.ExpectedOutput,
+		Metadata:            req.Metadata,
+		SourceTraceID:       req.SourceTraceID,
+		SourceObservationID: req.SourceObservationID,
+		Status:              req.Status,
+	}
+	res := api.DatasetItemResponse{}
+
+	if err := l.client.UpsertDatasetItem(ctx, apiReq, &res); err != nil {
+		return nil, err
+	}
+
+	if !res.IsSuccess() {
+		return nil, newAPIError("upsert dataset item", apiReq, res.Code, res.RawBody)
+	}
+
+	return &res.Item, nil
+}
+
+// GetDatasetItem fetches a dataset item by ID.
+func (l *Langfuse) GetDatasetItem(ctx context.Context, id string) (*model.DatasetItem, error) {
+	req := api.DatasetItemRequest{ID: id}
+	res := api.DatasetItemResponse{}
+
+	if err := l.client.DatasetItem(ctx, &req, &res); err != nil {
+		return nil, err
+	}
+
+	if !res.IsSuccess() {
+		return nil, newAPIError("dataset item", &req, res.Code, res.RawBody)
+	}
+
+	return &res.Item, nil
+}
+
+// DatasetItemQuery selects dataset items for ListDatasetItems.
+type DatasetItemQuery struct {
+	DatasetName         string
+	SourceTraceID       string
+	SourceObservationID string
+	PageSize            int
+}
+
+// ListDatasetItems returns an iterator over the dataset items matching query.
+func (l *Langfuse) ListDatasetItems(ctx context.Context, query DatasetItemQuery) iter.Seq2[*model.DatasetItem, error] {
+	return listPages(ctx, func(ctx context.Context, page int) ([]model.DatasetItem, model.PageMeta, error) {
+		req := api.DatasetItemsRequest{
+			Page:                page,
+			Limit:               query.PageSize,
+			DatasetName:         query.DatasetName,
+			SourceTraceID:       query.SourceTraceID,
+			SourceObservationID: query.SourceObservationID,
+		}
+		res := api.DatasetItemsResponse{}
+		if err := l.client.DatasetItems(ctx, &req, &res); err != nil {
+			return nil, model.PageMeta{}, err
+		}
+		if !res.IsSuccess() {
+			return nil, model.PageMeta{}, newAPIError("dataset items", &req, res.Code, res.RawBody)
+		}
+		return res.Data, res.Meta, nil
+	})
+}
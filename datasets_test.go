@@ -0,0 +1,158 @@
+package langfuse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+func TestCreateDataset(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/public/v2/datasets" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		var got map[string]any
+		_ = json.Unmarshal(body, &got)
+		if got["name"] != "golden" || got["description"] != "regression inputs" {
+			t.Errorf("body=%s", body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "ds-1", "name": "golden", "description": "regression inputs"}`))
+	}))
+	defer srv.Close()
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	ds, err := l.CreateDataset(context.Background(), CreateDatasetRequest{Name: "golden", Description: "regression inputs"})
+	if err != nil {
+		t.Fatalf("CreateDataset: %v", err)
+	}
+	if ds.ID != "ds-1" || ds.Name != "golden" {
+		t.Errorf("dataset=%+v", ds)
+	}
+
+	if _, err := l.CreateDataset(context.Background(), CreateDatasetRequest{}); err == nil {
+		t.Error("expected error for empty name")
+	}
+}
+
+func TestGetDataset_EscapesName(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/public/v2/datasets/evals%2Fqa" {
+			t.Errorf("path=%s", r.URL.EscapedPath())
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "ds-2", "name": "evals/qa"}`))
+	}))
+	defer srv.Close()
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	ds, err := l.GetDataset(context.Background(), "evals/qa")
+	if err != nil {
+		t.Fatalf("GetDataset: %v", err)
+	}
+	if ds.Name != "evals/qa" {
+		t.Errorf("dataset=%+v", ds)
+	}
+}
+
+func TestUpsertDatasetItem(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/public/dataset-items" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := io.ReadAll(r.Body)
+		var got map[string]any
+		_ = json.Unmarshal(body, &got)
+		if got["datasetName"] != "golden" || got["sourceTraceId"] != "t-1" || got["sourceObservationId"] != "o-1" {
+			t.Errorf("body=%s", body)
+		}
+		if got["expectedOutput"].(map[string]any)["answer"] != "42" {
+			t.Errorf("expectedOutput missing: %s", body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(append([]byte(`{"id": "item-1", "status": "ACTIVE", `), body[1:]...))
+	}))
+	defer srv.Close()
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	item, err := l.UpsertDatasetItem(context.Background(), UpsertDatasetItemRequest{
+		DatasetName:         "golden",
+		Input:               map[string]any{"question": "meaning of life"},
+		ExpectedOutput:      map[string]any{"answer": "42"},
+		Metadata:            map[string]any{"origin": "support-ticket"},
+		SourceTraceID:       "t-1",
+		SourceObservationID: "o-1",
+	})
+	if err != nil {
+		t.Fatalf("UpsertDatasetItem: %v", err)
+	}
+	if item.ID != "item-1" || item.Status != model.DatasetStatusActive || item.SourceTraceID != "t-1" {
+		t.Errorf("item=%+v", item)
+	}
+}
+
+func TestListDatasetItems_FollowsPages(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("datasetName"); got != "golden" {
+			t.Errorf("datasetName=%q", got)
+		}
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": []map[string]any{{"id": "item-" + strconv.Itoa(page), "datasetName": "golden"}},
+			"meta": map[string]any{"page": page, "limit": 1, "totalItems": 3, "totalPages": 3},
+		})
+	}))
+	defer srv.Close()
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	var ids []string
+	for item, err := range l.ListDatasetItems(context.Background(), DatasetItemQuery{DatasetName: "golden", PageSize: 1}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, item.ID)
+	}
+
+	if got := strings.Join(ids, ","); got != "item-1,item-2,item-3" {
+		t.Errorf("ids=%s", got)
+	}
+}
+
+func TestCreateDataset_APIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"message": "dataset already exists"}`))
+	}))
+	defer srv.Close()
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	_, err := l.CreateDataset(context.Background(), CreateDatasetRequest{Name: "golden"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict || apiErr.Message != "dataset already exists" {
+		t.Errorf("err=%v, want *APIError", err)
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"testing"
 	"time"
 
@@ -83,27 +82,37 @@ func TestEncoder_SpanCreate_EndTimePropagated(t *testing.T) {
 	}
 }
 
+// newStandIn starts an httptest server standing in for Langfuse and points
+// clients created with New during the test at it. The server and the
+// environment are restored when the test ends.
+func newStandIn(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	t.Setenv("LANGFUSE_HOST", srv.URL)
+	t.Setenv("LANGFUSE_PUBLIC_KEY", "test-pk")
+	t.Setenv("LANGFUSE_SECRET_KEY", "test-sk")
+	// Defensive: ensure no stray env from the host shell leaks in.
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+
+	return srv
+}
+
 // captureOTLP starts an httptest server, points the Langfuse client at it,
 // runs the caller-supplied operations, flushes, and returns the captured
 // raw OTLP request body.
 func captureOTLP(t *testing.T, do func(*Langfuse)) ([]byte, *httptest.Server) {
 	t.Helper()
 	var captured []byte
-	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	srv := newStandIn(t, func(w http.ResponseWriter, r *http.Request) {
 		b, err := io.ReadAll(r.Body)
 		if err != nil {
 			t.Fatalf("read OTLP body: %v", err)
 		}
 		captured = b
 		w.WriteHeader(http.StatusOK)
-	}))
-	t.Cleanup(srv.Close)
-
-	t.Setenv("LANGFUSE_HOST", srv.URL)
-	t.Setenv("LANGFUSE_PUBLIC_KEY", "test-pk")
-	t.Setenv("LANGFUSE_SECRET_KEY", "test-sk")
-	// Defensive: ensure no stray env from the host shell leaks in.
-	os.Unsetenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	})
 
 	lf := New(context.Background())
 	do(lf)
@@ -0,0 +1,100 @@
+package langfuse
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+// environmentEnvVar sets the default environment, as in the other Langfuse
+// SDKs.
+const environmentEnvVar = "LANGFUSE_TRACING_ENVIRONMENT"
+
+const maxEnvironmentLength = 40
+
+var environmentPattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// ValidateEnvironment reports whether env follows the Langfuse naming rules:
+// at most 40 lowercase letters, digits, hyphens and underscores, not starting
+// with the reserved "langfuse" prefix.
+func ValidateEnvironment(env string) error {
+	switch {
+	case env == "":
+		return fmt.Errorf("environment is required")
+	case len(env) > maxEnvironmentLength:
+		return fmt.Errorf("environment %q: must be at most %d characters", env, maxEnvironmentLength)
+	case !environmentPattern.MatchString(env):
+		return fmt.Errorf("environment %q: only lowercase letters, digits, '-' and '_' are allowed", env)
+	case strings.HasPrefix(env, "langfuse"):
+		return fmt.Errorf("environment %q: the \"langfuse\" prefix is reserved", env)
+	}
+	return nil
+}
+
+// validateOptionalEnvironment validates env unless it is empty.
+func validateOptionalEnvironment(env string) error {
+	if env == "" {
+		return nil
+	}
+	return ValidateEnvironment(env)
+}
+
+// WithEnvironment sets the environment recorded on every trace and
+// observation, unless a trace sets its own. It is also the default
+// environment of ListObservations and of cache lookups. An invalid
+// environment is logged and ignored. The default is read from
+// LANGFUSE_TRACING_ENVIRONMENT.
+func (l *Langfuse) WithEnvironment(env string) *Langfuse {
+	if err := ValidateEnvironment(env); err != nil {
+		log.Printf("WithEnvironment: %v", err)
+		return l
+	}
+	l.encoder.Environment = env
+	return l
+}
+
+// environment returns the client environment, or "" when none is set.
+func (l *Langfuse) environment() string {
+	return l.encoder.Environment
+}
+
+// environmentFromEnv returns LANGFUSE_TRACING_ENVIRONMENT if it is valid.
+func environmentFromEnv() string {
+	env := os.Getenv(environmentEnvVar)
+	if env == "" {
+		return ""
+	}
+	if err := ValidateEnvironment(env); err != nil {
+		log.Printf("%s: %v", environmentEnvVar, err)
+		return ""
+	}
+	return env
+}
+
+// stampEnvironment remembers the environment of trace events and sets it on
+// observations of the trace that do not set their own, so they keep it when
+// exported in a later batch than their trace.
+func (l *Langfuse) stampEnvironment(event model.IngestionEvent) {
+	switch body := event.Body.(type) {
+	case *model.Trace:
+		if body.Environment != "" {
+			l.traceEnvironments.set(body.ID, body.Environment)
+		}
+	case *model.Generation:
+		if body.Environment == "" {
+			body.Environment, _ = l.traceEnvironments.get(body.TraceID)
+		}
+	case *model.Span:
+		if body.Environment == "" {
+			body.Environment, _ = l.traceEnvironments.get(body.TraceID)
+		}
+	case *model.Event:
+		if body.Environment == "" {
+			body.Environment, _ = l.traceEnvironments.get(body.TraceID)
+		}
+	}
+}
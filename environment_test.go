@@ -0,0 +1,169 @@
+package langfuse
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/ezardev-team/langfuse-go/model"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracev1 "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestValidateEnvironment(t *testing.T) {
+	for env, valid := range map[string]bool{
+		"production":    true,
+		"staging-eu_1":  true,
+		"":              false,
+		"Production":    false,
+		"prod env":      false,
+		"langfuse-prod": false,
+		"a234567890123456789012345678901234567890":  true,
+		"a2345678901234567890123456789012345678901": false,
+	} {
+		if err := ValidateEnvironment(env); (err == nil) != valid {
+			t.Errorf("%q: err=%v, want valid=%v", env, err, valid)
+		}
+	}
+}
+
+func TestWithEnvironment_EncodesAttribute(t *testing.T) {
+	body, _ := captureOTLP(t, func(l *Langfuse) {
+		l.WithEnvironment("staging")
+
+		a, _ := l.Trace(&model.Trace{Name: "default-trace"})
+		_, _ = l.Span(&model.Span{TraceID: a.ID, Name: "default-span"}, nil)
+
+		b, err := l.Trace(&model.Trace{Name: "canary-trace", Environment: "canary"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = l.Span(&model.Span{TraceID: b.ID, Name: "canary-span"}, nil)
+
+		_, _ = l.Span(&model.Span{TraceID: "trace-from-earlier-batch", Name: "orphan-span"}, nil)
+	})
+
+	var req coltracepb.ExportTraceServiceRequest
+	if err := proto.Unmarshal(body, &req); err != nil {
+		t.Fatalf("unmarshal OTLP body: %v", err)
+	}
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+
+	for _, tt := range []struct {
+		name, obsType, want string
+	}{
+		{"default-span", "span", "staging"},
+		{"canary-span", "span", "canary"},
+		{"orphan-span", "span", "staging"},
+	} {
+		span := findObservationSpan(spans, tt.name, tt.obsType)
+		if span == nil {
+			t.Fatalf("span %q not found", tt.name)
+		}
+		if got, _ := spanAttr(span, "langfuse.environment"); got != tt.want {
+			t.Errorf("%s: langfuse.environment=%q, want %q", tt.name, got, tt.want)
+		}
+	}
+	assertTraceEnvironment(t, spans, "canary-trace", "canary")
+}
+
+func TestWithEnvironment_ObservationInLaterBatch(t *testing.T) {
+	bodies := make(chan []byte, 2)
+	newStandIn(t, func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("read OTLP body: %v", err)
+		}
+		bodies <- b
+		w.WriteHeader(http.StatusOK)
+	})
+
+	l := New(context.Background()).WithEnvironment("staging")
+	trace, err := l.Trace(&model.Trace{Name: "canary-trace", Environment: "canary"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.observer.Flush()
+	findSpan(t, <-bodies, "canary-trace")
+
+	_, _ = l.Generation(&model.Generation{TraceID: trace.ID, Name: "late-generation"}, nil)
+	l.Flush(context.Background())
+
+	span := findSpan(t, <-bodies, "late-generation")
+	if got, _ := spanAttr(span, "langfuse.environment"); got != "canary" {
+		t.Errorf("langfuse.environment=%q, want canary", got)
+	}
+}
+
+func assertTraceEnvironment(t *testing.T, spans []*tracev1.Span, name, want string) {
+	t.Helper()
+	for _, span := range spans {
+		if v, _ := spanAttr(span, "langfuse.trace.name"); v == name {
+			if got, _ := spanAttr(span, "langfuse.environment"); got != want {
+				t.Errorf("trace %s: langfuse.environment=%q, want %q", name, got, want)
+			}
+			return
+		}
+	}
+	t.Errorf("trace %s not found", name)
+}
+
+func TestWithEnvironment_IgnoresInvalid(t *testing.T) {
+	l := (&Langfuse{}).WithEnvironment("staging").WithEnvironment("Not Valid")
+	if l.environment() != "staging" {
+		t.Errorf("environment=%q, want staging", l.environment())
+	}
+}
+
+func TestTrace_RejectsInvalidEnvironment(t *testing.T) {
+	l := &Langfuse{}
+	if _, err := l.Trace(&model.Trace{Name: "t", Environment: "langfuse"}); err == nil {
+		t.Fatal("expected environment validation error")
+	}
+}
+
+func TestListObservations_DefaultEnvironment(t *testing.T) {
+	srv, queries := pagedObservationsServer(t, map[string][]string{"": {"a"}})
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+	l.WithEnvironment("staging")
+
+	for _, err := range l.ListObservations(context.Background(), ObservationQuery{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, err := range l.ListObservations(context.Background(), ObservationQuery{Environment: []string{"production"}}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := (*queries)[0].Get("environment"); got != "staging" {
+		t.Errorf("default environment=%q, want staging", got)
+	}
+	if got := (*queries)[1].Get("environment"); got != "production" {
+		t.Errorf("explicit environment=%q, want production", got)
+	}
+}
+
+func TestFindCachedGeneration_DefaultEnvironment(t *testing.T) {
+	srv, queries := pagedObservationsServer(t, map[string][]string{"": {}})
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+	l.WithEnvironment("staging")
+
+	options := &GenerationCacheOptions{Name: "summarize"}
+	if _, err := l.FindCachedGeneration(context.Background(), "key", options); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := (*queries)[0].Get("environment"); got != "staging" {
+		t.Errorf("environment=%q, want staging", got)
+	}
+	if options.Environment != "" {
+		t.Error("caller options must not be modified")
+	}
+}
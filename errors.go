@@ -0,0 +1,165 @@
+package langfuse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors matched by *APIError through errors.Is.
+var (
+	// ErrNotFound matches 404 responses, e.g. an unknown prompt or trace.
+	ErrNotFound = errors.New("langfuse: not found")
+	// ErrUnauthorized matches 401 responses: missing or invalid API keys.
+	ErrUnauthorized = errors.New("langfuse: unauthorized")
+	// ErrForbidden matches 403 responses: valid keys without access.
+	ErrForbidden = errors.New("langfuse: forbidden")
+	// ErrRateLimited matches 429 responses.
+	ErrRateLimited = errors.New("langfuse: rate limited")
+)
+
+// APIError is returned when the Langfuse API answers with an error status.
+// Use errors.As to inspect it, or errors.Is with the sentinel errors above:
+//
+//	if errors.Is(err, langfuse.ErrNotFound) { ... }
+type APIError struct {
+	// Operation names the SDK call that failed, e.g. "prompt".
+	Operation string
+	// Endpoint is the request path without the query string.
+	Endpoint   string
+	StatusCode int
+	// Message is the error message reported by Langfuse, if the body had one.
+	Message string
+	// Type is the Langfuse error class, e.g. "LangfuseNotFoundError".
+	Type string
+	// Body is the raw response body.
+	Body string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s request failed with status code: %d (path=%s)", e.Operation, e.StatusCode, e.Endpoint)
+	switch {
+	case e.Message != "":
+		return msg + ": " + e.Message
+	case e.Body != "":
+		return msg + " body=" + e.Body
+	default:
+		return msg
+	}
+}
+
+// Is reports whether e matches one of the sentinel errors.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	default:
+		return false
+	}
+}
+
+// Retryable reports whether repeating the request may succeed: timeouts,
+// rate limits and server-side failures.
+func (e *APIError) Retryable() bool {
+	switch e.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests,
+		http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsNotFound reports whether err is a 404 API error.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsUnauthorized reports whether err is a 401 API error.
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsForbidden reports whether err is a 403 API error.
+func IsForbidden(err error) bool {
+	return errors.Is(err, ErrForbidden)
+}
+
+// IsRateLimited reports whether err is a 429 API error.
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// IsRetryable reports whether err is an API error worth retrying.
+func IsRetryable(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Retryable()
+}
+
+// requestPather is implemented by every request type of the api package.
+type requestPather interface {
+	Path() (string, error)
+}
+
+// newAPIError builds an *APIError for a failed response to req.
+func newAPIError(operation string, req requestPather, code int, rawBody *string) *APIError {
+	apiErr := &APIError{
+		Operation:  operation,
+		StatusCode: code,
+	}
+
+	if path, err := req.Path(); err == nil {
+		apiErr.Endpoint, _, _ = strings.Cut(path, "?")
+	}
+
+	if rawBody != nil {
+		apiErr.Body = *rawBody
+		apiErr.Message, apiErr.Type = parseErrorBody(*rawBody)
+	}
+
+	return apiErr
+}
+
+// parseErrorBody extracts the message from Langfuse error bodies such as
+// {"message": "...", "error": "LangfuseNotFoundError"}. When only "error" is
+// present it is taken as the message.
+func parseErrorBody(body string) (message, errorType string) {
+	var parsed struct {
+		Message json.RawMessage `json:"message"`
+		Error   json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(body), &parsed); err != nil {
+		return "", ""
+	}
+
+	message = jsonText(parsed.Message)
+	errorType = jsonText(parsed.Error)
+	if message == "" {
+		return errorType, ""
+	}
+
+	return message, errorType
+}
+
+// jsonText returns a JSON string as-is and any other JSON value (e.g. a list
+// of validation issues) in its compact encoding.
+func jsonText(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+
+	return string(raw)
+}
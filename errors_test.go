@@ -0,0 +1,137 @@
+package langfuse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ezardev-team/langfuse-go/internal/pkg/api"
+)
+
+func TestAPIError_Sentinels(t *testing.T) {
+	tests := []struct {
+		code      int
+		sentinel  error
+		check     func(error) bool
+		retryable bool
+	}{
+		{http.StatusNotFound, ErrNotFound, IsNotFound, false},
+		{http.StatusUnauthorized, ErrUnauthorized, IsUnauthorized, false},
+		{http.StatusForbidden, ErrForbidden, IsForbidden, false},
+		{http.StatusTooManyRequests, ErrRateLimited, IsRateLimited, true},
+	}
+
+	for _, tt := range tests {
+		err := fmt.Errorf("wrapped: %w", newAPIError("prompt", &api.PromptRequest{Name: "p"}, tt.code, nil))
+
+		if !errors.Is(err, tt.sentinel) || !tt.check(err) {
+			t.Errorf("%d: must match %v", tt.code, tt.sentinel)
+		}
+		for _, other := range []error{ErrNotFound, ErrUnauthorized, ErrForbidden, ErrRateLimited} {
+			if other != tt.sentinel && errors.Is(err, other) {
+				t.Errorf("%d: must not match %v", tt.code, other)
+			}
+		}
+		if IsRetryable(err) != tt.retryable {
+			t.Errorf("%d: IsRetryable=%v, want %v", tt.code, !tt.retryable, tt.retryable)
+		}
+	}
+}
+
+func TestAPIError_Retryable(t *testing.T) {
+	for code, want := range map[int]bool{
+		http.StatusBadRequest:          false,
+		http.StatusConflict:            false,
+		http.StatusRequestTimeout:      true,
+		http.StatusInternalServerError: true,
+		http.StatusBadGateway:          true,
+		http.StatusServiceUnavailable:  true,
+		http.StatusGatewayTimeout:      true,
+	} {
+		if got := (&APIError{StatusCode: code}).Retryable(); got != want {
+			t.Errorf("%d: Retryable=%v, want %v", code, got, want)
+		}
+	}
+
+	if IsRetryable(errors.New("plain")) {
+		t.Error("non-API errors must not be retryable")
+	}
+}
+
+func TestNewAPIError_ParsesBody(t *testing.T) {
+	tests := []struct {
+		body        string
+		wantMessage string
+		wantType    string
+	}{
+		{`{"message":"Prompt not found","error":"LangfuseNotFoundError"}`, "Prompt not found", "LangfuseNotFoundError"},
+		{`{"error":"invalid prompt structure"}`, "invalid prompt structure", ""},
+		{`{"message":[{"path":["name"],"message":"Required"}]}`, `[{"path":["name"],"message":"Required"}]`, ""},
+		{`upstream error`, "", ""},
+	}
+
+	for _, tt := range tests {
+		body := tt.body
+		apiErr := newAPIError("traces", &api.TracesRequest{Page: 2}, http.StatusBadRequest, &body)
+
+		if apiErr.Message != tt.wantMessage || apiErr.Type != tt.wantType {
+			t.Errorf("%s: message=%q type=%q", tt.body, apiErr.Message, apiErr.Type)
+		}
+		if apiErr.Endpoint != "/api/public/traces" {
+			t.Errorf("endpoint=%q, want the path without query", apiErr.Endpoint)
+		}
+		if apiErr.Body != body {
+			t.Errorf("body=%q", apiErr.Body)
+		}
+		if !strings.Contains(apiErr.Error(), "400") {
+			t.Errorf("Error()=%q must contain the status", apiErr.Error())
+		}
+	}
+}
+
+func TestPrompt_NotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Prompt not found: 'missing'","error":"LangfuseNotFoundError"}`))
+	}))
+	defer srv.Close()
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	_, err := l.Prompt(context.Background(), "missing", nil)
+	if !IsNotFound(err) {
+		t.Fatalf("err=%v, want not found", err)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err=%T, want *APIError", err)
+	}
+	if apiErr.Endpoint != "/api/public/v2/prompts/missing" || apiErr.Type != "LangfuseNotFoundError" || apiErr.Operation != "prompt" {
+		t.Errorf("apiErr=%+v", apiErr)
+	}
+}
+
+func TestListObservations_RateLimited(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"message":"slow down"}`))
+	}))
+	defer srv.Close()
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	for _, err := range l.ListObservations(context.Background(), ObservationQuery{}) {
+		if !IsRateLimited(err) || !IsRetryable(err) {
+			t.Errorf("err=%v, want retryable rate limit", err)
+		}
+	}
+}
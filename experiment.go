@@ -0,0 +1,280 @@
+package langfuse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ezardev-team/langfuse-go/internal/pkg/api"
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+// ExperimentTask runs the code under test for one dataset item and returns
+// its output.
+type ExperimentTask func(ctx context.Context, item *model.DatasetItem) (any, error)
+
+// Evaluator scores the output of an ExperimentTask. Name is required and
+// must be unique within a run. Evaluate may return a nil score to skip the
+// item. The runner fills in the score's TraceID and, when empty, uses Name
+// as the score name.
+type Evaluator struct {
+	Name     string
+	Evaluate func(ctx context.Context, item *model.DatasetItem, output any) (*model.Score, error)
+}
+
+// ExperimentResult summarizes a RunExperiment call.
+type ExperimentResult struct {
+	DatasetName string
+	RunName     string
+	// Items holds one result per dataset item, in dataset order.
+	Items []ExperimentItemResult
+	// Evaluators holds one summary per evaluator name.
+	Evaluators map[string]*EvaluatorSummary
+	// Failures counts items whose task failed or whose trace could not be
+	// linked to the run.
+	Failures int
+}
+
+// ExperimentItemResult is the outcome of one dataset item.
+type ExperimentItemResult struct {
+	Item    *model.DatasetItem
+	TraceID string
+	Output  any
+	// Err is the task error or the error linking the trace to the run.
+	Err    error
+	Scores []model.Score
+}
+
+// EvaluatorSummary aggregates the scores of one evaluator.
+type EvaluatorSummary struct {
+	Name string
+	// Count is the number of scores recorded.
+	Count int
+	// Mean averages the NUMERIC and BOOLEAN scores; CATEGORICAL scores are
+	// counted but not averaged.
+	Mean float64
+	// Failures counts evaluator errors and scores that failed validation.
+	Failures int
+
+	sum     float64
+	numeric int
+}
+
+// RunExperiment runs task over every active item of the dataset and records
+// the run in Langfuse. For each item it creates a trace with a "task" span,
+// links the trace to the item as a dataset run item of runName, runs the
+// evaluators on the output and records their scores on the trace.
+//
+// Items are processed WithExperimentConcurrency at a time. Evaluators are
+// skipped for items whose task failed. The returned error is only set when
+// the dataset cannot be read or ctx is cancelled; per-item failures are
+// reported in the result. Traces and scores are sent asynchronously, so call
+// Flush before the process exits.
+func (l *Langfuse) RunExperiment(ctx context.Context, datasetName, runName string, task ExperimentTask, evaluators ...Evaluator) (*ExperimentResult, error) {
+	if datasetName == "" {
+		return nil, fmt.Errorf("dataset name is required")
+	}
+	if runName == "" {
+		return nil, fmt.Errorf("run name is required")
+	}
+	if task == nil {
+		return nil, fmt.Errorf("experiment task is required")
+	}
+	if err := validateEvaluators(evaluators); err != nil {
+		return nil, err
+	}
+
+	var items []*model.DatasetItem
+	for item, err := range l.ListDatasetItems(ctx, DatasetItemQuery{DatasetName: datasetName}) {
+		if err != nil {
+			return nil, err
+		}
+		if item.Status == model.DatasetStatusArchived {
+			continue
+		}
+		items = append(items, item)
+	}
+
+	result := &ExperimentResult{
+		DatasetName: datasetName,
+		RunName:     runName,
+		Items:       make([]ExperimentItemResult, len(items)),
+		Evaluators:  make(map[string]*EvaluatorSummary, len(evaluators)),
+	}
+	for _, evaluator := range evaluators {
+		result.Evaluators[evaluator.Name] = &EvaluatorSummary{Name: evaluator.Name}
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	sem := make(chan struct{}, max(l.experimentConcurrency, 1))
+
+	for i, item := range items {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, item *model.DatasetItem) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			itemResult := l.runExperimentItem(ctx, datasetName, runName, item, task)
+			if itemResult.Err == nil {
+				itemResult.Scores = l.evaluateExperimentItem(ctx, &itemResult, evaluators, result, &mu)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			result.Items[i] = itemResult
+			if itemResult.Err != nil {
+				result.Failures++
+			}
+		}(i, item)
+	}
+
+	wg.Wait()
+
+	for _, summary := range result.Evaluators {
+		if summary.numeric > 0 {
+			summary.Mean = summary.sum / float64(summary.numeric)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// validateEvaluators checks that every evaluator has an Evaluate function
+// and a unique, non-empty name, which keys its summary in the result.
+func validateEvaluators(evaluators []Evaluator) error {
+	names := make(map[string]bool, len(evaluators))
+	for i, evaluator := range evaluators {
+		if evaluator.Name == "" {
+			return fmt.Errorf("evaluator %d: name is required", i)
+		}
+		if names[evaluator.Name] {
+			return fmt.Errorf("evaluator %q: duplicate name", evaluator.Name)
+		}
+		if evaluator.Evaluate == nil {
+			return fmt.Errorf("evaluator %q: Evaluate is required", evaluator.Name)
+		}
+		names[evaluator.Name] = true
+	}
+	return nil
+}
+
+func (l *Langfuse) runExperimentItem(ctx context.Context, datasetName, runName string, item *model.DatasetItem, task ExperimentTask) ExperimentItemResult {
+	traceID := buildID(nil)
+	itemResult := ExperimentItemResult{Item: item, TraceID: traceID}
+	// Experiment runs are linked to their traces, so they bypass sampling.
+	if l.sampler != nil {
+		l.samplingDecisions.keep(traceID)
+	}
+
+	start := time.Now().UTC()
+	// The trace is created before the task runs so that the "task" span is
+	// exported with its trace context even when the task outlasts a flush;
+	// it is upserted with the output afterwards.
+	trace := &model.Trace{
+		ID:        traceID,
+		Name:      "experiment:" + runName,
+		Timestamp: &start,
+		Input:     item.Input,
+		Metadata: map[string]any{
+			"dataset_name":    datasetName,
+			"dataset_item_id": item.ID,
+			"run_name":        runName,
+		},
+	}
+	_, _ = l.Trace(trace)
+
+	span, spanErr := l.Span(&model.Span{
+		TraceID:   traceID,
+		Name:      "task",
+		StartTime: &start,
+		Input:     item.Input,
+	}, nil)
+
+	output, taskErr := task(ctx, item)
+	itemResult.Output = output
+
+	if spanErr == nil {
+		end := time.Now().UTC()
+		span.EndTime = &end
+		span.Output = output
+		span.RecordError(taskErr)
+		_, _ = l.SpanEnd(span)
+	}
+
+	withOutput := *trace
+	withOutput.Output = output
+	_, _ = l.Trace(&withOutput)
+
+	linkErr := l.linkDatasetRunItem(ctx, runName, item.ID, traceID)
+	itemResult.Err = errors.Join(taskErr, linkErr)
+
+	return itemResult
+}
+
+func (l *Langfuse) linkDatasetRunItem(ctx context.Context, runName, itemID, traceID string) error {
+	req := &api.DatasetRunItemCreateRequest{
+		RunName:       runName,
+		DatasetItemID: itemID,
+		TraceID:       traceID,
+	}
+	res := api.DatasetRunItemResponse{}
+
+	if err := l.client.CreateDatasetRunItem(ctx, req, &res); err != nil {
+		return fmt.Errorf("link dataset item %s to run %q: %w", itemID, runName, err)
+	}
+
+	if !res.IsSuccess() {
+		return newAPIError("dataset run item", req, res.Code, res.RawBody)
+	}
+
+	return nil
+}
+
+func (l *Langfuse) evaluateExperimentItem(ctx context.Context, itemResult *ExperimentItemResult, evaluators []Evaluator, result *ExperimentResult, mu *sync.Mutex) []model.Score {
+	var scores []model.Score
+
+	for _, evaluator := range evaluators {
+		score, err := evaluator.Evaluate(ctx, itemResult.Item, itemResult.Output)
+		if err == nil && score != nil {
+			score.TraceID = itemResult.TraceID
+			if score.Name == "" {
+				score.Name = evaluator.Name
+			}
+			_, err = l.Score(score)
+		}
+
+		mu.Lock()
+		summary := result.Evaluators[evaluator.Name]
+		switch {
+		case err != nil:
+			summary.Failures++
+		case score != nil:
+			summary.Count++
+			if score.EffectiveDataType() != model.ScoreDataTypeCategorical {
+				summary.sum += score.Value
+				summary.numeric++
+			}
+			scores = append(scores, *score)
+		}
+		mu.Unlock()
+	}
+
+	return scores
+}
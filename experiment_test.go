@@ -0,0 +1,234 @@
+package langfuse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+// experimentStandIn serves a fixed dataset and records run items and scores.
+type experimentStandIn struct {
+	mu       sync.Mutex
+	runItems []map[string]any
+	scores   []map[string]any
+}
+
+func newExperimentStandIn(t *testing.T, items string) *experimentStandIn {
+	t.Helper()
+	s := &experimentStandIn{}
+
+	newStandIn(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		switch r.URL.Path {
+		case "/api/public/dataset-items":
+			_, _ = w.Write([]byte(`{"data": ` + items + `, "meta": {"page": 1, "limit": 50, "totalItems": 3, "totalPages": 1}}`))
+		case "/api/public/dataset-run-items":
+			var decoded map[string]any
+			_ = json.Unmarshal(body, &decoded)
+			s.runItems = append(s.runItems, decoded)
+			_, _ = w.Write([]byte(`{"id": "run-item"}`))
+		case "/api/public/scores":
+			var decoded map[string]any
+			_ = json.Unmarshal(body, &decoded)
+			s.scores = append(s.scores, decoded)
+			_, _ = w.Write([]byte(`{"id": "score"}`))
+		case "/api/public/otel/v1/traces":
+			w.WriteHeader(http.StatusOK)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	return s
+}
+
+func TestRunExperiment(t *testing.T) {
+	standIn := newExperimentStandIn(t, `[
+		{"id": "i1", "input": "2+2", "expectedOutput": "4"},
+		{"id": "i2", "input": "3+3", "expectedOutput": "6"},
+		{"id": "i3", "input": "boom", "expectedOutput": "x"},
+		{"id": "i4", "input": "old", "status": "ARCHIVED"}
+	]`)
+
+	ctx := context.Background()
+	lf := New(ctx).WithExperimentConcurrency(2)
+
+	answers := map[string]string{"2+2": "4", "3+3": "7"}
+	task := func(ctx context.Context, item *model.DatasetItem) (any, error) {
+		answer, ok := answers[item.Input.(string)]
+		if !ok {
+			return nil, errors.New("cannot answer")
+		}
+		return answer, nil
+	}
+	exact := Evaluator{
+		Name: "exact_match",
+		Evaluate: func(ctx context.Context, item *model.DatasetItem, output any) (*model.Score, error) {
+			value := 0.0
+			if output == item.ExpectedOutput {
+				value = 1
+			}
+			return &model.Score{DataType: model.ScoreDataTypeBoolean, Value: value}, nil
+		},
+	}
+	broken := Evaluator{
+		Name: "broken",
+		Evaluate: func(ctx context.Context, item *model.DatasetItem, output any) (*model.Score, error) {
+			return nil, errors.New("judge unavailable")
+		},
+	}
+
+	result, err := lf.RunExperiment(ctx, "arithmetic", "run-1", task, exact, broken)
+	if err != nil {
+		t.Fatalf("RunExperiment: %v", err)
+	}
+	lf.Flush(ctx)
+
+	if len(result.Items) != 3 {
+		t.Fatalf("items=%d, want 3 (archived item skipped)", len(result.Items))
+	}
+	if result.Failures != 1 || result.Items[2].Err == nil {
+		t.Errorf("failures=%d, want the third item to fail", result.Failures)
+	}
+	if result.Items[0].Output != "4" || len(result.Items[0].Scores) != 1 {
+		t.Errorf("first item=%+v", result.Items[0])
+	}
+
+	em := result.Evaluators["exact_match"]
+	if em.Count != 2 || math.Abs(em.Mean-0.5) > 1e-9 || em.Failures != 0 {
+		t.Errorf("exact_match=%+v, want count 2 mean 0.5", em)
+	}
+	if b := result.Evaluators["broken"]; b.Failures != 2 || b.Count != 0 {
+		t.Errorf("broken=%+v, want 2 failures", b)
+	}
+
+	standIn.mu.Lock()
+	defer standIn.mu.Unlock()
+
+	if len(standIn.runItems) != 3 {
+		t.Fatalf("run items=%d, want 3", len(standIn.runItems))
+	}
+	linked := map[string]string{}
+	for _, ri := range standIn.runItems {
+		if ri["runName"] != "run-1" {
+			t.Errorf("runName=%v", ri["runName"])
+		}
+		linked[ri["datasetItemId"].(string)] = ri["traceId"].(string)
+	}
+	for _, item := range result.Items {
+		if linked[item.Item.ID] != item.TraceID || item.TraceID == "" {
+			t.Errorf("item %s linked to %q, want %q", item.Item.ID, linked[item.Item.ID], item.TraceID)
+		}
+	}
+
+	if len(standIn.scores) != 2 {
+		t.Fatalf("scores=%d, want 2", len(standIn.scores))
+	}
+	for _, s := range standIn.scores {
+		if s["name"] != "exact_match" || s["dataType"] != "BOOLEAN" || s["traceId"] == "" {
+			t.Errorf("score=%v", s)
+		}
+	}
+}
+
+func TestRunExperiment_DatasetError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"dataset not found"}`))
+	}))
+	defer srv.Close()
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	_, err := l.RunExperiment(context.Background(), "missing", "run", func(ctx context.Context, item *model.DatasetItem) (any, error) {
+		t.Fatal("task must not run")
+		return nil, nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("err=%v, want dataset status error", err)
+	}
+}
+
+func TestRunExperiment_TraceDispatchedBeforeSpan(t *testing.T) {
+	newExperimentStandIn(t, `[{"id": "i1", "input": "2+2"}]`)
+
+	var (
+		mu    sync.Mutex
+		order []model.IngestionEventType
+	)
+	ctx := context.Background()
+	lf := New(ctx).WithEventProcessor(EventProcessorFunc(func(_ context.Context, event *model.IngestionEvent) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, event.Type)
+		return true
+	}))
+
+	result, err := lf.RunExperiment(ctx, "arithmetic", "run-1", func(ctx context.Context, item *model.DatasetItem) (any, error) {
+		return "4", nil
+	})
+	if err != nil {
+		t.Fatalf("RunExperiment: %v", err)
+	}
+	lf.Flush(ctx)
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []model.IngestionEventType{
+		model.IngestionEventTypeTraceCreate,
+		model.IngestionEventTypeSpanCreate,
+		model.IngestionEventTypeSpanUpdate,
+		model.IngestionEventTypeTraceCreate,
+	}
+	if len(order) != len(want) {
+		t.Fatalf("events=%v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Errorf("events=%v, want %v", order, want)
+			break
+		}
+	}
+	if result.Items[0].Err != nil {
+		t.Errorf("item err=%v", result.Items[0].Err)
+	}
+}
+
+func TestRunExperiment_InvalidEvaluators(t *testing.T) {
+	l := &Langfuse{}
+	task := func(ctx context.Context, item *model.DatasetItem) (any, error) { return nil, nil }
+	evaluate := func(ctx context.Context, item *model.DatasetItem, output any) (*model.Score, error) { return nil, nil }
+
+	for name, evaluators := range map[string][]Evaluator{
+		"empty name":   {{Evaluate: evaluate}},
+		"duplicate":    {{Name: "a", Evaluate: evaluate}, {Name: "a", Evaluate: evaluate}},
+		"nil evaluate": {{Name: "a"}},
+	} {
+		if _, err := l.RunExperiment(context.Background(), "ds", "run", task, evaluators...); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if _, err := l.RunExperiment(context.Background(), "ds", "run", nil); err == nil {
+		t.Error("nil task: expected an error")
+	}
+	if _, err := l.RunExperiment(context.Background(), "", "run", task); err == nil {
+		t.Error("empty dataset name: expected an error")
+	}
+}
@@ -0,0 +1,179 @@
+package langfuse
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"sync"
+
+	"github.com/ezardev-team/langfuse-go/internal/pkg/api"
+	"github.com/ezardev-team/langfuse-go/internal/pkg/otel"
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+// ExportStats is a snapshot of the background exporter counters.
+type ExportStats struct {
+	// Batches counts OTLP export requests sent.
+	Batches uint64
+	// FailedBatches counts OTLP export requests that failed outright.
+	FailedBatches uint64
+	// Events counts trace and observation events handed to the exporter.
+	Events uint64
+	// RejectedSpans counts spans the OTLP endpoint reported as rejected in a
+	// partial success response.
+	RejectedSpans uint64
+	// Scores counts scores posted to the scores API.
+	Scores uint64
+	// FailedScores counts scores that could not be posted.
+	FailedScores uint64
+	// Truncations counts inputs and outputs shortened to fit the size limits.
+	Truncations uint64
+	// MediaUploads counts media sent to presigned upload URLs.
+	MediaUploads uint64
+	// FailedMediaUploads counts media uploads that failed.
+	FailedMediaUploads uint64
+	// Unsampled counts events dropped because their trace was not sampled.
+	Unsampled uint64
+	// Filtered counts events dropped by event processors.
+	Filtered uint64
+}
+
+// PartialExportError reports an OTLP export the endpoint accepted only in
+// part. RejectedSpans may be zero when the endpoint only sent a warning.
+type PartialExportError struct {
+	RejectedSpans int64
+	Message       string
+}
+
+func (e *PartialExportError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("otlp export: %d spans rejected", e.RejectedSpans)
+	}
+	return fmt.Sprintf("otlp export: %d spans rejected: %s", e.RejectedSpans, e.Message)
+}
+
+// exportStats guards ExportStats for the concurrent exporter runs.
+type exportStats struct {
+	mu    sync.Mutex
+	stats ExportStats
+}
+
+func (s *exportStats) update(fn func(*ExportStats)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(&s.stats)
+}
+
+func (s *exportStats) snapshot() ExportStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+// OTLPEncoding is the wire format of trace exports.
+type OTLPEncoding string
+
+const (
+	// OTLPEncodingProtobuf sends binary protobuf, the default.
+	OTLPEncodingProtobuf OTLPEncoding = "protobuf"
+	// OTLPEncodingJSON sends OTLP/JSON, for proxies that inspect bodies.
+	OTLPEncodingJSON OTLPEncoding = "json"
+)
+
+// WithOTLPEncoding sets the wire format of trace exports.
+func (l *Langfuse) WithOTLPEncoding(encoding OTLPEncoding) *Langfuse {
+	l.otlpEncoding = encoding
+	return l
+}
+
+// WithGzip enables gzip compression of trace exports, sent with a
+// "Content-Encoding: gzip" header.
+func (l *Langfuse) WithGzip(enabled bool) *Langfuse {
+	l.gzip = enabled
+	return l
+}
+
+// WithMetadataLimits bounds how trace and observation metadata is expanded
+// into OTLP attributes: maxKeys caps the attributes per metadata value and
+// maxDepth the levels of nested maps flattened into dotted keys. Deeper maps
+// are sent as JSON strings. Zero keeps the defaults of 128 keys and depth 3.
+func (l *Langfuse) WithMetadataLimits(maxKeys, maxDepth int) *Langfuse {
+	l.encoder.MetadataLimits = otel.MetadataLimits{MaxKeys: maxKeys, MaxDepth: maxDepth}
+	return l
+}
+
+// WithSizeLimits bounds exported payloads: maxFieldBytes caps each input and
+// output, maxSpanBytes the attribute values of one span. Payloads over a
+// limit are truncated to valid JSON ending in a "…[truncated]" marker, and
+// the span records the original size as a
+// "langfuse_go.original_bytes.<attribute>" attribute. Zero keeps the defaults
+// of 1 MiB per field and 4 MiB per span; a negative value disables a limit.
+// Payloads are never cut below 16 bytes, the size of the marker.
+func (l *Langfuse) WithSizeLimits(maxFieldBytes, maxSpanBytes int) *Langfuse {
+	l.encoder.SizeLimits = otel.SizeLimits{MaxFieldBytes: maxFieldBytes, MaxSpanBytes: maxSpanBytes}
+	return l
+}
+
+// WithExportErrorHandler sets fn to receive the errors of background exports:
+// failed requests, *APIError responses and *PartialExportError reports. By
+// default they are printed to stdout.
+func (l *Langfuse) WithExportErrorHandler(fn func(error)) *Langfuse {
+	l.exportErrorHandler = fn
+	return l
+}
+
+// Stats returns a snapshot of the background exporter counters.
+func (l *Langfuse) Stats() ExportStats {
+	return l.exportStats.snapshot()
+}
+
+func (l *Langfuse) handleExportError(err error) {
+	if l.exportErrorHandler != nil {
+		l.exportErrorHandler(err)
+		return
+	}
+	fmt.Println(err)
+}
+
+// exportRequest encodes events in the configured wire format and compression.
+func (l *Langfuse) exportRequest(events []model.IngestionEvent) (*api.OpenTelemetryTracesRequest, error) {
+	req := &api.OpenTelemetryTracesRequest{}
+
+	encoder := l.encoder
+	encoder.OnTruncate = func() {
+		l.exportStats.update(func(s *ExportStats) { s.Truncations++ })
+	}
+
+	var err error
+	if l.otlpEncoding == OTLPEncodingJSON {
+		req.Body, err = encoder.EncodeJSON(events)
+		req.ContentTypeOverride = api.ContentTypeJSON
+	} else {
+		req.Body, err = encoder.Encode(events)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if l.gzip {
+		req.Body, err = gzipBytes(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("gzip export: %w", err)
+		}
+		req.ContentEncoding = api.ContentEncodingGzip
+	}
+
+	return req, nil
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
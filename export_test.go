@@ -0,0 +1,152 @@
+package langfuse
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ezardev-team/langfuse-go/internal/pkg/api"
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+// newOTLPStandIn answers OTLP exports with the given status and JSON body.
+func newOTLPStandIn(t *testing.T, status int, body string) {
+	t.Helper()
+
+	newStandIn(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/public/otel/v1/traces" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	})
+}
+
+func TestExport_PartialSuccess(t *testing.T) {
+	newOTLPStandIn(t, http.StatusOK, `{"partialSuccess": {"rejectedSpans": "2", "errorMessage": "span too large"}}`)
+
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+	ctx := context.Background()
+	l := New(ctx).WithExportErrorHandler(func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	})
+
+	if _, err := l.Trace(&model.Trace{Name: "trace"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Span(&model.Span{Name: "span"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	l.Flush(ctx)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(errs) != 1 {
+		t.Fatalf("handler errors=%v, want one", errs)
+	}
+	var partial *PartialExportError
+	if !errors.As(errs[0], &partial) || partial.RejectedSpans != 2 || partial.Message != "span too large" {
+		t.Errorf("err=%v, want partial export error", errs[0])
+	}
+
+	stats := l.Stats()
+	if stats.Batches != 1 || stats.RejectedSpans != 2 || stats.FailedBatches != 0 {
+		t.Errorf("stats=%+v", stats)
+	}
+	if stats.Events < 2 {
+		t.Errorf("events=%d, want at least 2", stats.Events)
+	}
+}
+
+func TestExport_Failure(t *testing.T) {
+	newOTLPStandIn(t, http.StatusServiceUnavailable, `{"message": "ingestion paused"}`)
+
+	l := &Langfuse{client: api.New()}
+	err := l.ingest(context.Background(), []model.IngestionEvent{
+		{Type: model.IngestionEventTypeTraceCreate, Body: &model.Trace{ID: "t-1", Name: "trace"}},
+	})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "ingestion paused" || !IsRetryable(err) {
+		t.Fatalf("err=%v, want retryable APIError", err)
+	}
+
+	stats := l.Stats()
+	if stats.Batches != 1 || stats.FailedBatches != 1 || stats.RejectedSpans != 0 {
+		t.Errorf("stats=%+v", stats)
+	}
+}
+
+func TestExport_GzipJSON(t *testing.T) {
+	var (
+		contentType, contentEncoding string
+		body                         []byte
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		contentEncoding = r.Header.Get("Content-Encoding")
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Errorf("gzip reader: %v", err)
+			return
+		}
+		body, _ = io.ReadAll(zr)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	t.Setenv("LANGFUSE_HOST", srv.URL)
+
+	l := (&Langfuse{client: api.New()}).WithGzip(true).WithOTLPEncoding(OTLPEncodingJSON)
+	err := l.ingest(context.Background(), []model.IngestionEvent{
+		{Type: model.IngestionEventTypeTraceCreate, Body: &model.Trace{ID: "t-1", Name: "trace"}},
+	})
+	if err != nil {
+		t.Fatalf("ingest: %v", err)
+	}
+
+	if contentType != api.ContentTypeJSON || contentEncoding != "gzip" {
+		t.Errorf("Content-Type=%q Content-Encoding=%q", contentType, contentEncoding)
+	}
+	if !json.Valid(body) || !strings.Contains(string(body), `"resourceSpans"`) {
+		t.Errorf("body=%s, want OTLP/JSON", body)
+	}
+}
+
+func TestExport_SizeLimits(t *testing.T) {
+	var size int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		size = len(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	t.Setenv("LANGFUSE_HOST", srv.URL)
+
+	l := (&Langfuse{client: api.New()}).WithSizeLimits(1024, -1)
+	err := l.ingest(context.Background(), []model.IngestionEvent{
+		{Type: model.IngestionEventTypeTraceCreate, Body: &model.Trace{ID: "t-1", Name: "trace", Input: strings.Repeat("x", 1<<20)}},
+	})
+	if err != nil {
+		t.Fatalf("ingest: %v", err)
+	}
+
+	if size == 0 || size > 8<<10 {
+		t.Errorf("body=%d bytes, want a truncated export", size)
+	}
+	if stats := l.Stats(); stats.Truncations == 0 {
+		t.Errorf("stats=%+v, want truncations", stats)
+	}
+}
@@ -0,0 +1,300 @@
+package langfuse
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Filter value types understood by the Langfuse filter API.
+const (
+	filterTypeString        = "string"
+	filterTypeNumber        = "number"
+	filterTypeDatetime      = "datetime"
+	filterTypeStringOptions = "stringOptions"
+	filterTypeArrayOptions  = "arrayOptions"
+	filterTypeStringObject  = "stringObject"
+)
+
+// FilterCondition is one condition of a Langfuse filter expression. Build it
+// from a typed column (StringColumn, NumberColumn, ...), which only offers
+// the operators the API accepts for that column type. Conditions passed
+// together are combined with AND.
+type FilterCondition struct {
+	filterType string
+	column     string
+	operator   string
+	key        string
+	value      any
+}
+
+// Column returns the column the condition applies to.
+func (c FilterCondition) Column() string {
+	return c.column
+}
+
+// Operator returns the condition operator, e.g. "=" or "any of".
+func (c FilterCondition) Operator() string {
+	return c.operator
+}
+
+// Validate reports a condition the API would reject, so mistakes surface
+// before a request is sent.
+func (c FilterCondition) Validate() error {
+	if c.column == "" {
+		return fmt.Errorf("filter condition: column is required")
+	}
+	if c.filterType == "" || c.operator == "" {
+		return fmt.Errorf("filter condition on %q: build it from a typed column", c.column)
+	}
+	if c.filterType == filterTypeStringObject && c.key == "" {
+		return fmt.Errorf("filter condition on %q: key is required", c.column)
+	}
+
+	switch v := c.value.(type) {
+	case []string:
+		if len(v) == 0 {
+			return fmt.Errorf("filter condition on %q: %q needs at least one value", c.column, c.operator)
+		}
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("filter condition on %q: value must be a finite number", c.column)
+		}
+	case time.Time:
+		if v.IsZero() {
+			return fmt.Errorf("filter condition on %q: time is required", c.column)
+		}
+	}
+
+	return nil
+}
+
+type filterConditionJSON struct {
+	Type     string `json:"type"`
+	Column   string `json:"column"`
+	Operator string `json:"operator"`
+	Value    any    `json:"value"`
+	Key      string `json:"key,omitempty"`
+}
+
+// MarshalJSON encodes the condition in the API wire format.
+func (c FilterCondition) MarshalJSON() ([]byte, error) {
+	value := c.value
+	if t, ok := value.(time.Time); ok {
+		value = t.UTC().Format(time.RFC3339Nano)
+	}
+
+	return marshalFilterJSON(filterConditionJSON{
+		Type:     c.filterType,
+		Column:   c.column,
+		Operator: c.operator,
+		Value:    value,
+		Key:      c.key,
+	})
+}
+
+// marshalFilterJSON encodes v without HTML escaping, so operators such as
+// ">=" stay readable in the query string.
+func marshalFilterJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// encodeFilter validates conditions and encodes them as the JSON filter
+// query parameter. It returns "" when there are no conditions.
+func encodeFilter(conditions []FilterCondition) (string, error) {
+	if len(conditions) == 0 {
+		return "", nil
+	}
+
+	for _, c := range conditions {
+		if err := c.Validate(); err != nil {
+			return "", err
+		}
+	}
+
+	data, err := marshalFilterJSON(conditions)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode filter: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// StringColumn is a free-text column.
+type StringColumn string
+
+func (c StringColumn) condition(operator, value string) FilterCondition {
+	return FilterCondition{filterType: filterTypeString, column: string(c), operator: operator, value: value}
+}
+
+// Eq matches values equal to value.
+func (c StringColumn) Eq(value string) FilterCondition { return c.condition("=", value) }
+
+// Contains matches values containing value.
+func (c StringColumn) Contains(value string) FilterCondition { return c.condition("contains", value) }
+
+// DoesNotContain matches values not containing value.
+func (c StringColumn) DoesNotContain(value string) FilterCondition {
+	return c.condition("does not contain", value)
+}
+
+// StartsWith matches values starting with value.
+func (c StringColumn) StartsWith(value string) FilterCondition {
+	return c.condition("starts with", value)
+}
+
+// EndsWith matches values ending with value.
+func (c StringColumn) EndsWith(value string) FilterCondition { return c.condition("ends with", value) }
+
+// NumberColumn is a numeric column.
+type NumberColumn string
+
+func (c NumberColumn) condition(operator string, value float64) FilterCondition {
+	return FilterCondition{filterType: filterTypeNumber, column: string(c), operator: operator, value: value}
+}
+
+// Eq matches values equal to value.
+func (c NumberColumn) Eq(value float64) FilterCondition { return c.condition("=", value) }
+
+// Gt matches values greater than value.
+func (c NumberColumn) Gt(value float64) FilterCondition { return c.condition(">", value) }
+
+// Gte matches values greater than or equal to value.
+func (c NumberColumn) Gte(value float64) FilterCondition { return c.condition(">=", value) }
+
+// Lt matches values less than value.
+func (c NumberColumn) Lt(value float64) FilterCondition { return c.condition("<", value) }
+
+// Lte matches values less than or equal to value.
+func (c NumberColumn) Lte(value float64) FilterCondition { return c.condition("<=", value) }
+
+// DatetimeColumn is a timestamp column.
+type DatetimeColumn string
+
+func (c DatetimeColumn) condition(operator string, value time.Time) FilterCondition {
+	return FilterCondition{filterType: filterTypeDatetime, column: string(c), operator: operator, value: value}
+}
+
+// After matches timestamps strictly after t.
+func (c DatetimeColumn) After(t time.Time) FilterCondition { return c.condition(">", t) }
+
+// AtOrAfter matches timestamps at or after t.
+func (c DatetimeColumn) AtOrAfter(t time.Time) FilterCondition { return c.condition(">=", t) }
+
+// Before matches timestamps strictly before t.
+func (c DatetimeColumn) Before(t time.Time) FilterCondition { return c.condition("<", t) }
+
+// AtOrBefore matches timestamps at or before t.
+func (c DatetimeColumn) AtOrBefore(t time.Time) FilterCondition { return c.condition("<=", t) }
+
+// StringOptionsColumn is a column holding one value out of a fixed set.
+type StringOptionsColumn string
+
+// AnyOf matches rows whose value is one of values.
+func (c StringOptionsColumn) AnyOf(values ...string) FilterCondition {
+	return FilterCondition{filterType: filterTypeStringOptions, column: string(c), operator: "any of", value: values}
+}
+
+// NoneOf matches rows whose value is none of values.
+func (c StringOptionsColumn) NoneOf(values ...string) FilterCondition {
+	return FilterCondition{filterType: filterTypeStringOptions, column: string(c), operator: "none of", value: values}
+}
+
+// ArrayOptionsColumn is a column holding a list of values, such as tags.
+type ArrayOptionsColumn string
+
+// AnyOf matches rows containing at least one of values.
+func (c ArrayOptionsColumn) AnyOf(values ...string) FilterCondition {
+	return FilterCondition{filterType: filterTypeArrayOptions, column: string(c), operator: "any of", value: values}
+}
+
+// AllOf matches rows containing every one of values.
+func (c ArrayOptionsColumn) AllOf(values ...string) FilterCondition {
+	return FilterCondition{filterType: filterTypeArrayOptions, column: string(c), operator: "all of", value: values}
+}
+
+// NoneOf matches rows containing none of values.
+func (c ArrayOptionsColumn) NoneOf(values ...string) FilterCondition {
+	return FilterCondition{filterType: filterTypeArrayOptions, column: string(c), operator: "none of", value: values}
+}
+
+// StringObjectColumn is a key/value column such as metadata.
+type StringObjectColumn string
+
+// Key selects one key of the object column.
+func (c StringObjectColumn) Key(key string) StringObjectKey {
+	return StringObjectKey{column: string(c), key: key}
+}
+
+// StringObjectKey is one key of a StringObjectColumn.
+type StringObjectKey struct {
+	column string
+	key    string
+}
+
+func (k StringObjectKey) condition(operator string, value any) FilterCondition {
+	return FilterCondition{filterType: filterTypeStringObject, column: k.column, key: k.key, operator: operator, value: value}
+}
+
+// Eq matches values equal to value.
+func (k StringObjectKey) Eq(value string) FilterCondition { return k.condition("=", value) }
+
+// Contains matches values containing value.
+func (k StringObjectKey) Contains(value string) FilterCondition {
+	return k.condition("contains", value)
+}
+
+// DoesNotContain matches values not containing value.
+func (k StringObjectKey) DoesNotContain(value string) FilterCondition {
+	return k.condition("does not contain", value)
+}
+
+// StartsWith matches values starting with value.
+func (k StringObjectKey) StartsWith(value string) FilterCondition {
+	return k.condition("starts with", value)
+}
+
+// EndsWith matches values ending with value.
+func (k StringObjectKey) EndsWith(value string) FilterCondition {
+	return k.condition("ends with", value)
+}
+
+// Matches matches values equal to value, tolerating surrounding JSON quotes
+// on the stored value.
+func (k StringObjectKey) Matches(value string) FilterCondition {
+	return k.condition("matches", value)
+}
+
+// AnyOf matches values equal to one of values.
+func (k StringObjectKey) AnyOf(values ...string) FilterCondition {
+	return k.condition("any of", values)
+}
+
+// Observation columns for ObservationQuery.Filters.
+const (
+	ObservationColumnName          StringColumn        = "name"
+	ObservationColumnType          StringColumn        = "type"
+	ObservationColumnTraceID       StringColumn        = "traceId"
+	ObservationColumnUserID        StringColumn        = "userId"
+	ObservationColumnSessionID     StringColumn        = "sessionId"
+	ObservationColumnVersion       StringColumn        = "version"
+	ObservationColumnModel         StringColumn        = "providedModelName"
+	ObservationColumnPromptName    StringColumn        = "promptName"
+	ObservationColumnPromptVersion NumberColumn        = "promptVersion"
+	ObservationColumnLatency       NumberColumn        = "latency"
+	ObservationColumnTotalCost     NumberColumn        = "totalCost"
+	ObservationColumnStartTime     DatetimeColumn      = "startTime"
+	ObservationColumnEndTime       DatetimeColumn      = "endTime"
+	ObservationColumnLevel         StringOptionsColumn = "level"
+	ObservationColumnEnvironment   StringOptionsColumn = "environment"
+	ObservationColumnTags          ArrayOptionsColumn  = "tags"
+	ObservationColumnMetadata      StringObjectColumn  = "metadata"
+)
@@ -0,0 +1,113 @@
+package langfuse
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncodeFilter_WireFormat(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 30, 0, 0, time.FixedZone("KST", 9*60*60))
+
+	got, err := encodeFilter([]FilterCondition{
+		ObservationColumnName.Contains("summar"),
+		ObservationColumnLatency.Gte(1.5),
+		ObservationColumnStartTime.Before(at),
+		ObservationColumnLevel.NoneOf("DEBUG"),
+		ObservationColumnTags.AllOf("prod", "eu"),
+		ObservationColumnMetadata.Key("tenant").Eq("acme"),
+	})
+	if err != nil {
+		t.Fatalf("encodeFilter: %v", err)
+	}
+
+	want := `[` +
+		`{"type":"string","column":"name","operator":"contains","value":"summar"},` +
+		`{"type":"number","column":"latency","operator":">=","value":1.5},` +
+		`{"type":"datetime","column":"startTime","operator":"<","value":"2024-05-01T03:30:00Z"},` +
+		`{"type":"stringOptions","column":"level","operator":"none of","value":["DEBUG"]},` +
+		`{"type":"arrayOptions","column":"tags","operator":"all of","value":["prod","eu"]},` +
+		`{"type":"stringObject","column":"metadata","operator":"=","value":"acme","key":"tenant"}` +
+		`]`
+	if got != want {
+		t.Errorf("filter=\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestEncodeFilter_Empty(t *testing.T) {
+	got, err := encodeFilter(nil)
+	if err != nil || got != "" {
+		t.Errorf("filter=%q err=%v, want empty", got, err)
+	}
+}
+
+func TestFilterCondition_Validate(t *testing.T) {
+	tests := map[string]FilterCondition{
+		"zero value":   {},
+		"empty any of": ObservationColumnLevel.AnyOf(),
+		"NaN":          ObservationColumnTotalCost.Lt(math.NaN()),
+		"infinity":     ObservationColumnTotalCost.Lt(math.Inf(1)),
+		"zero time":    ObservationColumnEndTime.After(time.Time{}),
+		"missing key":  ObservationColumnMetadata.Key("").Eq("x"),
+	}
+	for name, c := range tests {
+		if err := c.Validate(); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+		if _, err := encodeFilter([]FilterCondition{ObservationColumnName.Eq("ok"), c}); err == nil {
+			t.Errorf("%s: encodeFilter must reject invalid conditions", name)
+		}
+	}
+
+	if err := ObservationColumnPromptVersion.Eq(0).Validate(); err != nil {
+		t.Errorf("zero is a valid number: %v", err)
+	}
+}
+
+func TestFilterCondition_Accessors(t *testing.T) {
+	c := ObservationColumnMetadata.Key("cache_key").Matches("v1:abc")
+	if c.Column() != "metadata" || c.Operator() != "matches" {
+		t.Errorf("column=%q operator=%q", c.Column(), c.Operator())
+	}
+}
+
+// TestListObservations_Filters verifies conditions reach the API as the
+// JSON filter parameter and that invalid ones fail without a request.
+func TestListObservations_Filters(t *testing.T) {
+	srv, queries := pagedObservationsServer(t, map[string][]string{"": {"a"}})
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	for _, err := range l.ListObservations(context.Background(), ObservationQuery{
+		Filters: []FilterCondition{ObservationColumnMetadata.Key("tenant").Eq("acme")},
+	}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var conds []map[string]any
+	if err := json.Unmarshal([]byte((*queries)[0].Get("filter")), &conds); err != nil {
+		t.Fatalf("decode filter: %v", err)
+	}
+	if len(conds) != 1 || conds[0]["key"] != "tenant" || conds[0]["value"] != "acme" {
+		t.Errorf("filter=%v", conds)
+	}
+
+	var gotErr error
+	for _, err := range l.ListObservations(context.Background(), ObservationQuery{
+		Filters: []FilterCondition{ObservationColumnLevel.AnyOf()},
+	}) {
+		gotErr = err
+	}
+	if gotErr == nil || !strings.Contains(gotErr.Error(), "level") {
+		t.Errorf("err=%v, want validation error", gotErr)
+	}
+	if len(*queries) != 1 {
+		t.Errorf("requests=%d, want no request for an invalid filter", len(*queries))
+	}
+}
@@ -1,8 +1,11 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
+	"fmt"
+	"io"
 	"net/http"
 	"os"
 
@@ -15,6 +18,11 @@ const (
 
 type Client struct {
 	restClient *restclientgo.RestClient
+	// gzipRestClient sends requests whose body is already gzip-compressed.
+	gzipRestClient *restclientgo.RestClient
+	// httpClient sends media to presigned upload URLs, which are not on the
+	// Langfuse host and must not carry its credentials.
+	httpClient *http.Client
 }
 
 func New() *Client {
@@ -26,19 +34,33 @@ func New() *Client {
 	publicKey := os.Getenv("LANGFUSE_PUBLIC_KEY")
 	secretKey := os.Getenv("LANGFUSE_SECRET_KEY")
 
+	authorization := basicAuth(publicKey, secretKey)
+
 	restClient := restclientgo.New(langfuseHost)
 	restClient.SetRequestModifier(func(req *http.Request) *http.Request {
-		req.Header.Set("Authorization", basicAuth(publicKey, secretKey))
+		req.Header.Set("Authorization", authorization)
+		return req
+	})
+
+	gzipRestClient := restclientgo.New(langfuseHost)
+	gzipRestClient.SetRequestModifier(func(req *http.Request) *http.Request {
+		req.Header.Set("Authorization", authorization)
+		req.Header.Set("Content-Encoding", ContentEncodingGzip)
 		return req
 	})
 
 	return &Client{
-		restClient: restClient,
+		restClient:     restClient,
+		gzipRestClient: gzipRestClient,
+		httpClient:     http.DefaultClient,
 	}
 }
 
 // OpenTelemetryTraces posts OTLP/HTTP traces to Langfuse.
 func (c *Client) OpenTelemetryTraces(ctx context.Context, req *OpenTelemetryTracesRequest, res *OpenTelemetryResponse) error {
+	if req.ContentEncoding == ContentEncodingGzip {
+		return c.gzipRestClient.Post(ctx, req, res)
+	}
 	return c.restClient.Post(ctx, req, res)
 }
 
@@ -56,6 +78,114 @@ func (c *Client) Observations(ctx context.Context, req *ObservationsRequest, res
 	return c.restClient.Get(ctx, req, res)
 }
 
+func (c *Client) Trace(ctx context.Context, req *TraceRequest, res *TraceResponse) error {
+	return c.restClient.Get(ctx, req, res)
+}
+
+func (c *Client) Traces(ctx context.Context, req *TracesRequest, res *TracesResponse) error {
+	return c.restClient.Get(ctx, req, res)
+}
+
+func (c *Client) Session(ctx context.Context, req *SessionRequest, res *SessionResponse) error {
+	return c.restClient.Get(ctx, req, res)
+}
+
+func (c *Client) Sessions(ctx context.Context, req *SessionsRequest, res *SessionsResponse) error {
+	return c.restClient.Get(ctx, req, res)
+}
+
+// CreateScore POSTs a score to Langfuse `/api/public/scores`.
+func (c *Client) CreateScore(ctx context.Context, req *ScoreCreateRequest, res *ScoreCreateResponse) error {
+	return c.restClient.Post(ctx, req, res)
+}
+
+func (c *Client) Score(ctx context.Context, req *ScoreRequest, res *ScoreResponse) error {
+	return c.restClient.Get(ctx, req, res)
+}
+
+func (c *Client) Scores(ctx context.Context, req *ScoresRequest, res *ScoresResponse) error {
+	return c.restClient.Get(ctx, req, res)
+}
+
+func (c *Client) ScoreConfig(ctx context.Context, req *ScoreConfigRequest, res *ScoreConfigResponse) error {
+	return c.restClient.Get(ctx, req, res)
+}
+
+func (c *Client) ScoreConfigs(ctx context.Context, req *ScoreConfigsRequest, res *ScoreConfigsResponse) error {
+	return c.restClient.Get(ctx, req, res)
+}
+
+func (c *Client) CreateDataset(ctx context.Context, req *DatasetCreateRequest, res *DatasetResponse) error {
+	return c.restClient.Post(ctx, req, res)
+}
+
+func (c *Client) Dataset(ctx context.Context, req *DatasetRequest, res *DatasetResponse) error {
+	return c.restClient.Get(ctx, req, res)
+}
+
+func (c *Client) Datasets(ctx context.Context, req *DatasetsRequest, res *DatasetsResponse) error {
+	return c.restClient.Get(ctx, req, res)
+}
+
+// UpsertDatasetItem POSTs a dataset item; an existing ID is updated in place.
+func (c *Client) UpsertDatasetItem(ctx context.Context, req *DatasetItemUpsertRequest, res *DatasetItemResponse) error {
+	return c.restClient.Post(ctx, req, res)
+}
+
+func (c *Client) DatasetItem(ctx context.Context, req *DatasetItemRequest, res *DatasetItemResponse) error {
+	return c.restClient.Get(ctx, req, res)
+}
+
+func (c *Client) DatasetItems(ctx context.Context, req *DatasetItemsRequest, res *DatasetItemsResponse) error {
+	return c.restClient.Get(ctx, req, res)
+}
+
+// CreateDatasetRunItem links a trace to a dataset item within a run.
+func (c *Client) CreateDatasetRunItem(ctx context.Context, req *DatasetRunItemCreateRequest, res *DatasetRunItemResponse) error {
+	return c.restClient.Post(ctx, req, res)
+}
+
+// CreateMediaUpload POSTs to Langfuse `/api/public/media` to link content to
+// a trace or observation and obtain a presigned upload URL.
+func (c *Client) CreateMediaUpload(ctx context.Context, req *MediaUploadRequest, res *MediaUploadResponse) error {
+	return c.restClient.Post(ctx, req, res)
+}
+
+// UpdateMedia PATCHes `/api/public/media/{mediaId}` with the upload outcome.
+func (c *Client) UpdateMedia(ctx context.Context, req *MediaPatchRequest, res *MediaPatchResponse) error {
+	return c.restClient.Patch(ctx, req, res)
+}
+
+// UploadMedia PUTs data to a presigned uploadURL and returns the HTTP status.
+// A status of 400 or above is returned together with an error carrying the
+// response body.
+func (c *Client) UploadMedia(ctx context.Context, uploadURL, contentType, sha256Hash string, data []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uploadURL, bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("x-amz-checksum-sha256", sha256Hash)
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusBadRequest {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1<<10))
+		return res.StatusCode, fmt.Errorf("media upload: status %d: %s", res.StatusCode, bytes.TrimSpace(body))
+	}
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	return res.StatusCode, nil
+}
+
+func (c *Client) Metrics(ctx context.Context, req *MetricsRequest, res *MetricsResponse) error {
+	return c.restClient.Get(ctx, req, res)
+}
+
 func basicAuth(publicKey, secretKey string) string {
 	auth := publicKey + ":" + secretKey
 	return "Basic " + base64.StdEncoding.EncodeToString([]byte(auth))
@@ -14,6 +14,8 @@ import (
 const (
 	ContentTypeJSON     = "application/json"
 	ContentTypeProtobuf = "application/x-protobuf"
+
+	ContentEncodingGzip = "gzip"
 )
 
 type Request struct{}
@@ -22,6 +24,9 @@ type OpenTelemetryTracesRequest struct {
 	Body                []byte
 	PathOverride        string
 	ContentTypeOverride string
+	// ContentEncoding is set to ContentEncodingGzip when Body is already
+	// gzip-compressed.
+	ContentEncoding string
 }
 
 func (t *OpenTelemetryTracesRequest) Path() (string, error) {
@@ -158,6 +163,621 @@ func (o *ObservationsRequest) ContentType() string {
 	return ""
 }
 
+// TraceRequest is the request for `GET /api/public/traces/{traceId}`.
+type TraceRequest struct {
+	ID string
+}
+
+func (t *TraceRequest) Path() (string, error) {
+	if t.ID == "" {
+		return "", fmt.Errorf("trace ID is required")
+	}
+
+	return "/api/public/traces/" + url.PathEscape(t.ID), nil
+}
+
+func (t *TraceRequest) Encode() (io.Reader, error) {
+	return nil, nil
+}
+
+func (t *TraceRequest) ContentType() string {
+	return ""
+}
+
+// TracesRequest is the request for `GET /api/public/traces`.
+//
+// Pagination is page-based: Page starts at 1. Tags and Environment may hold
+// several values; a trace must carry every tag and match any environment.
+type TracesRequest struct {
+	Page          int
+	Limit         int
+	UserID        string
+	Name          string
+	SessionID     string
+	FromTimestamp *time.Time
+	ToTimestamp   *time.Time
+	OrderBy       string
+	Tags          []string
+	Version       string
+	Release       string
+	Environment   []string
+}
+
+func (t *TracesRequest) Path() (string, error) {
+	queryParams := url.Values{}
+
+	if t.Page > 0 {
+		queryParams.Set("page", fmt.Sprintf("%d", t.Page))
+	}
+
+	if t.Limit > 0 {
+		queryParams.Set("limit", fmt.Sprintf("%d", t.Limit))
+	}
+
+	if t.UserID != "" {
+		queryParams.Set("userId", t.UserID)
+	}
+
+	if t.Name != "" {
+		queryParams.Set("name", t.Name)
+	}
+
+	if t.SessionID != "" {
+		queryParams.Set("sessionId", t.SessionID)
+	}
+
+	if t.FromTimestamp != nil {
+		queryParams.Set("fromTimestamp", t.FromTimestamp.Format(time.RFC3339))
+	}
+
+	if t.ToTimestamp != nil {
+		queryParams.Set("toTimestamp", t.ToTimestamp.Format(time.RFC3339))
+	}
+
+	if t.OrderBy != "" {
+		queryParams.Set("orderBy", t.OrderBy)
+	}
+
+	for _, tag := range t.Tags {
+		if tag != "" {
+			queryParams.Add("tags", tag)
+		}
+	}
+
+	if t.Version != "" {
+		queryParams.Set("version", t.Version)
+	}
+
+	if t.Release != "" {
+		queryParams.Set("release", t.Release)
+	}
+
+	for _, environment := range t.Environment {
+		if environment != "" {
+			queryParams.Add("environment", environment)
+		}
+	}
+
+	path := "/api/public/traces"
+	if encodedQuery := queryParams.Encode(); encodedQuery != "" {
+		path += "?" + encodedQuery
+	}
+
+	return path, nil
+}
+
+func (t *TracesRequest) Encode() (io.Reader, error) {
+	return nil, nil
+}
+
+func (t *TracesRequest) ContentType() string {
+	return ""
+}
+
+// SessionRequest is the request for `GET /api/public/sessions/{sessionId}`.
+type SessionRequest struct {
+	ID string
+}
+
+func (s *SessionRequest) Path() (string, error) {
+	if s.ID == "" {
+		return "", fmt.Errorf("session ID is required")
+	}
+
+	return "/api/public/sessions/" + url.PathEscape(s.ID), nil
+}
+
+func (s *SessionRequest) Encode() (io.Reader, error) {
+	return nil, nil
+}
+
+func (s *SessionRequest) ContentType() string {
+	return ""
+}
+
+// SessionsRequest is the request for `GET /api/public/sessions`. Pagination
+// is page-based; Page starts at 1.
+type SessionsRequest struct {
+	Page          int
+	Limit         int
+	FromTimestamp *time.Time
+	ToTimestamp   *time.Time
+	Environment   []string
+}
+
+func (s *SessionsRequest) Path() (string, error) {
+	queryParams := url.Values{}
+
+	if s.Page > 0 {
+		queryParams.Set("page", fmt.Sprintf("%d", s.Page))
+	}
+
+	if s.Limit > 0 {
+		queryParams.Set("limit", fmt.Sprintf("%d", s.Limit))
+	}
+
+	if s.FromTimestamp != nil {
+		queryParams.Set("fromTimestamp", s.FromTimestamp.Format(time.RFC3339))
+	}
+
+	if s.ToTimestamp != nil {
+		queryParams.Set("toTimestamp", s.ToTimestamp.Format(time.RFC3339))
+	}
+
+	for _, environment := range s.Environment {
+		if environment != "" {
+			queryParams.Add("environment", environment)
+		}
+	}
+
+	path := "/api/public/sessions"
+	if encodedQuery := queryParams.Encode(); encodedQuery != "" {
+		path += "?" + encodedQuery
+	}
+
+	return path, nil
+}
+
+func (s *SessionsRequest) Encode() (io.Reader, error) {
+	return nil, nil
+}
+
+func (s *SessionsRequest) ContentType() string {
+	return ""
+}
+
+// ScoreCreateRequest is the request body for `POST /api/public/scores`.
+// Value is a number for NUMERIC and BOOLEAN scores and the category label
+// for CATEGORICAL ones.
+type ScoreCreateRequest struct {
+	ID            string              `json:"id,omitempty"`
+	TraceID       string              `json:"traceId,omitempty"`
+	ObservationID string              `json:"observationId,omitempty"`
+	Name          string              `json:"name"`
+	Value         any                 `json:"value"`
+	DataType      model.ScoreDataType `json:"dataType,omitempty"`
+	Comment       string              `json:"comment,omitempty"`
+	ConfigID      string              `json:"configId,omitempty"`
+}
+
+func (s *ScoreCreateRequest) Path() (string, error) {
+	if s.Name == "" {
+		return "", fmt.Errorf("score name is required")
+	}
+	return "/api/public/scores", nil
+}
+
+func (s *ScoreCreateRequest) Encode() (io.Reader, error) {
+	body, err := json.Marshal(s)
+	if err != nil {
+		return nil, fmt.Errorf("encode ScoreCreateRequest: %w", err)
+	}
+	return bytes.NewReader(body), nil
+}
+
+func (s *ScoreCreateRequest) ContentType() string {
+	return ContentTypeJSON
+}
+
+// ScoreRequest is the request for `GET /api/public/v2/scores/{scoreId}`.
+type ScoreRequest struct {
+	ID string
+}
+
+func (s *ScoreRequest) Path() (string, error) {
+	if s.ID == "" {
+		return "", fmt.Errorf("score ID is required")
+	}
+
+	return "/api/public/v2/scores/" + url.PathEscape(s.ID), nil
+}
+
+func (s *ScoreRequest) Encode() (io.Reader, error) {
+	return nil, nil
+}
+
+func (s *ScoreRequest) ContentType() string {
+	return ""
+}
+
+// ScoresRequest is the request for `GET /api/public/v2/scores`. Pagination
+// is page-based; Page starts at 1.
+type ScoresRequest struct {
+	Page          int
+	Limit         int
+	UserID        string
+	Name          string
+	Source        model.ScoreSource
+	DataType      model.ScoreDataType
+	ConfigID      string
+	FromTimestamp *time.Time
+	ToTimestamp   *time.Time
+	Environment   []string
+	TraceTags     []string
+}
+
+func (s *ScoresRequest) Path() (string, error) {
+	queryParams := url.Values{}
+
+	if s.Page > 0 {
+		queryParams.Set("page", fmt.Sprintf("%d", s.Page))
+	}
+
+	if s.Limit > 0 {
+		queryParams.Set("limit", fmt.Sprintf("%d", s.Limit))
+	}
+
+	if s.UserID != "" {
+		queryParams.Set("userId", s.UserID)
+	}
+
+	if s.Name != "" {
+		queryParams.Set("name", s.Name)
+	}
+
+	if s.Source != "" {
+		queryParams.Set("source", string(s.Source))
+	}
+
+	if s.DataType != "" {
+		queryParams.Set("dataType", string(s.DataType))
+	}
+
+	if s.ConfigID != "" {
+		queryParams.Set("configId", s.ConfigID)
+	}
+
+	if s.FromTimestamp != nil {
+		queryParams.Set("fromTimestamp", s.FromTimestamp.Format(time.RFC3339))
+	}
+
+	if s.ToTimestamp != nil {
+		queryParams.Set("toTimestamp", s.ToTimestamp.Format(time.RFC3339))
+	}
+
+	for _, environment := range s.Environment {
+		if environment != "" {
+			queryParams.Add("environment", environment)
+		}
+	}
+
+	for _, tag := range s.TraceTags {
+		if tag != "" {
+			queryParams.Add("traceTags", tag)
+		}
+	}
+
+	path := "/api/public/v2/scores"
+	if encodedQuery := queryParams.Encode(); encodedQuery != "" {
+		path += "?" + encodedQuery
+	}
+
+	return path, nil
+}
+
+func (s *ScoresRequest) Encode() (io.Reader, error) {
+	return nil, nil
+}
+
+func (s *ScoresRequest) ContentType() string {
+	return ""
+}
+
+// ScoreConfigRequest is the request for
+// `GET /api/public/score-configs/{configId}`.
+type ScoreConfigRequest struct {
+	ID string
+}
+
+func (s *ScoreConfigRequest) Path() (string, error) {
+	if s.ID == "" {
+		return "", fmt.Errorf("score config ID is required")
+	}
+
+	return "/api/public/score-configs/" + url.PathEscape(s.ID), nil
+}
+
+func (s *ScoreConfigRequest) Encode() (io.Reader, error) {
+	return nil, nil
+}
+
+func (s *ScoreConfigRequest) ContentType() string {
+	return ""
+}
+
+// ScoreConfigsRequest is the request for `GET /api/public/score-configs`.
+type ScoreConfigsRequest struct {
+	Page  int
+	Limit int
+}
+
+func (s *ScoreConfigsRequest) Path() (string, error) {
+	queryParams := url.Values{}
+
+	if s.Page > 0 {
+		queryParams.Set("page", fmt.Sprintf("%d", s.Page))
+	}
+
+	if s.Limit > 0 {
+		queryParams.Set("limit", fmt.Sprintf("%d", s.Limit))
+	}
+
+	path := "/api/public/score-configs"
+	if encodedQuery := queryParams.Encode(); encodedQuery != "" {
+		path += "?" + encodedQuery
+	}
+
+	return path, nil
+}
+
+func (s *ScoreConfigsRequest) Encode() (io.Reader, error) {
+	return nil, nil
+}
+
+func (s *ScoreConfigsRequest) ContentType() string {
+	return ""
+}
+
+// DatasetCreateRequest is the request body for `POST /api/public/v2/datasets`.
+type DatasetCreateRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Metadata    any    `json:"metadata,omitempty"`
+}
+
+func (d *DatasetCreateRequest) Path() (string, error) {
+	if d.Name == "" {
+		return "", fmt.Errorf("dataset name is required")
+	}
+	return "/api/public/v2/datasets", nil
+}
+
+func (d *DatasetCreateRequest) Encode() (io.Reader, error) {
+	body, err := json.Marshal(d)
+	if err != nil {
+		return nil, fmt.Errorf("encode DatasetCreateRequest: %w", err)
+	}
+	return bytes.NewReader(body), nil
+}
+
+func (d *DatasetCreateRequest) ContentType() string {
+	return ContentTypeJSON
+}
+
+// DatasetRequest is the request for `GET /api/public/v2/datasets/{datasetName}`.
+type DatasetRequest struct {
+	Name string
+}
+
+func (d *DatasetRequest) Path() (string, error) {
+	if d.Name == "" {
+		return "", fmt.Errorf("dataset name is required")
+	}
+
+	return "/api/public/v2/datasets/" + url.PathEscape(d.Name), nil
+}
+
+func (d *DatasetRequest) Encode() (io.Reader, error) {
+	return nil, nil
+}
+
+func (d *DatasetRequest) ContentType() string {
+	return ""
+}
+
+// DatasetsRequest is the request for `GET /api/public/v2/datasets`.
+type DatasetsRequest struct {
+	Page  int
+	Limit int
+}
+
+func (d *DatasetsRequest) Path() (string, error) {
+	queryParams := url.Values{}
+
+	if d.Page > 0 {
+		queryParams.Set("page", fmt.Sprintf("%d", d.Page))
+	}
+
+	if d.Limit > 0 {
+		queryParams.Set("limit", fmt.Sprintf("%d", d.Limit))
+	}
+
+	path := "/api/public/v2/datasets"
+	if encodedQuery := queryParams.Encode(); encodedQuery != "" {
+		path += "?" + encodedQuery
+	}
+
+	return path, nil
+}
+
+func (d *DatasetsRequest) Encode() (io.Reader, error) {
+	return nil, nil
+}
+
+func (d *DatasetsRequest) ContentType() string {
+	return ""
+}
+
+// DatasetItemUpsertRequest is the request body for
+// `POST /api/public/dataset-items`. Sending an existing ID updates the item.
+type DatasetItemUpsertRequest struct {
+	ID                  string              `json:"id,omitempty"`
+	DatasetName         string              `json:"datasetName"`
+	Input               any                 `json:"input,omitempty"`
+	ExpectedOutput      any                 `json:"expectedOutput,omitempty"`
+	Metadata            any                 `json:"metadata,omitempty"`
+	SourceTraceID       string              `json:"sourceTraceId,omitempty"`
+	SourceObservationID string              `json:"sourceObservationId,omitempty"`
+	Status              model.DatasetStatus `json:"status,omitempty"`
+}
+
+func (d *DatasetItemUpsertRequest) Path() (string, error) {
+	if d.DatasetName == "" {
+		return "", fmt.Errorf("dataset name is required")
+	}
+	return "/api/public/dataset-items", nil
+}
+
+func (d *DatasetItemUpsertRequest) Encode() (io.Reader, error) {
+	body, err := json.Marshal(d)
+	if err != nil {
+		return nil, fmt.Errorf("encode DatasetItemUpsertRequest: %w", err)
+	}
+	return bytes.NewReader(body), nil
+}
+
+func (d *DatasetItemUpsertRequest) ContentType() string {
+	return ContentTypeJSON
+}
+
+// DatasetItemRequest is the request for `GET /api/public/dataset-items/{id}`.
+type DatasetItemRequest struct {
+	ID string
+}
+
+func (d *DatasetItemRequest) Path() (string, error) {
+	if d.ID == "" {
+		return "", fmt.Errorf("dataset item ID is required")
+	}
+
+	return "/api/public/dataset-items/" + url.PathEscape(d.ID), nil
+}
+
+func (d *DatasetItemRequest) Encode() (io.Reader, error) {
+	return nil, nil
+}
+
+func (d *DatasetItemRequest) ContentType() string {
+	return ""
+}
+
+// DatasetItemsRequest is the request for `GET /api/public/dataset-items`.
+type DatasetItemsRequest struct {
+	Page                int
+	Limit               int
+	DatasetName         string
+	SourceTraceID       string
+	SourceObservationID string
+}
+
+func (d *DatasetItemsRequest) Path() (string, error) {
+	queryParams := url.Values{}
+
+	if d.Page > 0 {
+		queryParams.Set("page", fmt.Sprintf("%d", d.Page))
+	}
+
+	if d.Limit > 0 {
+		queryParams.Set("limit", fmt.Sprintf("%d", d.Limit))
+	}
+
+	if d.DatasetName != "" {
+		queryParams.Set("datasetName", d.DatasetName)
+	}
+
+	if d.SourceTraceID != "" {
+		queryParams.Set("sourceTraceId", d.SourceTraceID)
+	}
+
+	if d.SourceObservationID != "" {
+		queryParams.Set("sourceObservationId", d.SourceObservationID)
+	}
+
+	path := "/api/public/dataset-items"
+	if encodedQuery := queryParams.Encode(); encodedQuery != "" {
+		path += "?" + encodedQuery
+	}
+
+	return path, nil
+}
+
+func (d *DatasetItemsRequest) Encode() (io.Reader, error) {
+	return nil, nil
+}
+
+func (d *DatasetItemsRequest) ContentType() string {
+	return ""
+}
+
+// DatasetRunItemCreateRequest is the request body for
+// `POST /api/public/dataset-run-items`. The run is created on first use of
+// RunName.
+type DatasetRunItemCreateRequest struct {
+	RunName        string `json:"runName"`
+	RunDescription string `json:"runDescription,omitempty"`
+	Metadata       any    `json:"metadata,omitempty"`
+	DatasetItemID  string `json:"datasetItemId"`
+	TraceID        string `json:"traceId,omitempty"`
+	ObservationID  string `json:"observationId,omitempty"`
+}
+
+func (d *DatasetRunItemCreateRequest) Path() (string, error) {
+	if d.RunName == "" {
+		return "", fmt.Errorf("run name is required")
+	}
+	if d.DatasetItemID == "" {
+		return "", fmt.Errorf("dataset item ID is required")
+	}
+	return "/api/public/dataset-run-items", nil
+}
+
+func (d *DatasetRunItemCreateRequest) Encode() (io.Reader, error) {
+	body, err := json.Marshal(d)
+	if err != nil {
+		return nil, fmt.Errorf("encode DatasetRunItemCreateRequest: %w", err)
+	}
+	return bytes.NewReader(body), nil
+}
+
+func (d *DatasetRunItemCreateRequest) ContentType() string {
+	return ContentTypeJSON
+}
+
+// MetricsRequest is the request for `GET /api/public/metrics`. Query is the
+// JSON-encoded metrics query.
+type MetricsRequest struct {
+	Query string
+}
+
+func (m *MetricsRequest) Path() (string, error) {
+	if m.Query == "" {
+		return "", fmt.Errorf("metrics query is required")
+	}
+
+	queryParams := url.Values{}
+	queryParams.Set("query", m.Query)
+
+	return "/api/public/metrics?" + queryParams.Encode(), nil
+}
+
+func (m *MetricsRequest) Encode() (io.Reader, error) {
+	return nil, nil
+}
+
+func (m *MetricsRequest) ContentType() string {
+	return ""
+}
+
 type PromptRequest struct {
 	Name        string
 	Version     *int
@@ -229,3 +849,66 @@ func (p *PromptUpsertRequest) Encode() (io.Reader, error) {
 func (p *PromptUpsertRequest) ContentType() string {
 	return ContentTypeJSON
 }
+
+// MediaUploadRequest is the request body for `POST /api/public/media`. It
+// links content to a trace or observation field and returns a presigned
+// upload URL unless content with the same SHA256Hash was uploaded before.
+// SHA256Hash is the base64-encoded SHA-256 of the content.
+type MediaUploadRequest struct {
+	TraceID       string `json:"traceId"`
+	ObservationID string `json:"observationId,omitempty"`
+	// MediaType is the MIME type of the content, sent as "contentType".
+	MediaType     string `json:"contentType"`
+	ContentLength int    `json:"contentLength"`
+	SHA256Hash    string `json:"sha256Hash"`
+	// Field is "input", "output" or "metadata".
+	Field string `json:"field"`
+}
+
+func (m *MediaUploadRequest) Path() (string, error) {
+	if m.TraceID == "" {
+		return "", fmt.Errorf("trace ID is required")
+	}
+	return "/api/public/media", nil
+}
+
+func (m *MediaUploadRequest) Encode() (io.Reader, error) {
+	body, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("encode MediaUploadRequest: %w", err)
+	}
+	return bytes.NewReader(body), nil
+}
+
+func (m *MediaUploadRequest) ContentType() string {
+	return ContentTypeJSON
+}
+
+// MediaPatchRequest is the request body for
+// `PATCH /api/public/media/{mediaId}`, reporting the outcome of an upload.
+type MediaPatchRequest struct {
+	MediaID          string    `json:"-"`
+	UploadedAt       time.Time `json:"uploadedAt"`
+	UploadHTTPStatus int       `json:"uploadHttpStatus"`
+	UploadHTTPError  string    `json:"uploadHttpError,omitempty"`
+	UploadTimeMs     int64     `json:"uploadTimeMs"`
+}
+
+func (m *MediaPatchRequest) Path() (string, error) {
+	if m.MediaID == "" {
+		return "", fmt.Errorf("media ID is required")
+	}
+	return "/api/public/media/" + url.PathEscape(m.MediaID), nil
+}
+
+func (m *MediaPatchRequest) Encode() (io.Reader, error) {
+	body, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("encode MediaPatchRequest: %w", err)
+	}
+	return bytes.NewReader(body), nil
+}
+
+func (m *MediaPatchRequest) ContentType() string {
+	return ContentTypeJSON
+}
@@ -403,3 +403,142 @@ func TestObservationsRequest_Encode(t *testing.T) {
 		t.Error("expected nil reader for GET request")
 	}
 }
+
+// --- TraceRequest / TracesRequest tests ---
+
+func TestTraceRequest_Path(t *testing.T) {
+	req := &TraceRequest{ID: "req/42"}
+	path, err := req.Path()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path != "/api/public/traces/req%2F42" {
+		t.Errorf("expected escaped trace path, got %s", path)
+	}
+}
+
+func TestTraceRequest_Path_EmptyID(t *testing.T) {
+	req := &TraceRequest{}
+	if _, err := req.Path(); err == nil {
+		t.Fatal("expected error for empty trace ID")
+	}
+}
+
+func TestTracesRequest_Path_NoParams(t *testing.T) {
+	req := &TracesRequest{}
+	path, err := req.Path()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path != "/api/public/traces" {
+		t.Errorf("expected /api/public/traces, got %s", path)
+	}
+}
+
+func TestTracesRequest_Path_AllParams(t *testing.T) {
+	from := time.Date(2024, 6, 15, 10, 30, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	req := &TracesRequest{
+		Page:          2,
+		Limit:         50,
+		UserID:        "user-1",
+		Name:          "checkout",
+		SessionID:     "sess-1",
+		FromTimestamp: &from,
+		ToTimestamp:   &to,
+		OrderBy:       "timestamp.desc",
+		Tags:          []string{"prod", "", "eu"},
+		Version:       "v1",
+		Release:       "r1",
+		Environment:   []string{"production", "staging"},
+	}
+
+	path, err := req.Path()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	u, err := url.Parse(path)
+	if err != nil {
+		t.Fatalf("failed to parse path: %v", err)
+	}
+	if u.Path != "/api/public/traces" {
+		t.Errorf("expected /api/public/traces, got %s", u.Path)
+	}
+
+	q := u.Query()
+	expected := map[string]string{
+		"page":          "2",
+		"limit":         "50",
+		"userId":        "user-1",
+		"name":          "checkout",
+		"sessionId":     "sess-1",
+		"fromTimestamp": from.Format(time.RFC3339),
+		"toTimestamp":   to.Format(time.RFC3339),
+		"orderBy":       "timestamp.desc",
+		"version":       "v1",
+		"release":       "r1",
+	}
+	for key, want := range expected {
+		if got := q.Get(key); got != want {
+			t.Errorf("expected %s=%s, got %s", key, want, got)
+		}
+	}
+	if got := strings.Join(q["tags"], ","); got != "prod,eu" {
+		t.Errorf("expected tags=prod,eu, got %s", got)
+	}
+	if got := strings.Join(q["environment"], ","); got != "production,staging" {
+		t.Errorf("expected environment=production,staging, got %s", got)
+	}
+}
+
+// --- SessionRequest / SessionsRequest tests ---
+
+func TestSessionRequest_Path(t *testing.T) {
+	req := &SessionRequest{ID: "chat 1"}
+	path, err := req.Path()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if path != "/api/public/sessions/chat%201" {
+		t.Errorf("expected escaped session path, got %s", path)
+	}
+
+	if _, err := (&SessionRequest{}).Path(); err == nil {
+		t.Fatal("expected error for empty session ID")
+	}
+}
+
+func TestSessionsRequest_Path_AllParams(t *testing.T) {
+	from := time.Date(2024, 6, 15, 10, 30, 0, 0, time.UTC)
+	req := &SessionsRequest{
+		Page:          3,
+		Limit:         20,
+		FromTimestamp: &from,
+		Environment:   []string{"production"},
+	}
+
+	path, err := req.Path()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	u, err := url.Parse(path)
+	if err != nil {
+		t.Fatalf("failed to parse path: %v", err)
+	}
+	if u.Path != "/api/public/sessions" {
+		t.Errorf("expected /api/public/sessions, got %s", u.Path)
+	}
+
+	q := u.Query()
+	if q.Get("page") != "3" || q.Get("limit") != "20" || q.Get("environment") != "production" {
+		t.Errorf("unexpected query: %v", q)
+	}
+	if q.Get("fromTimestamp") != from.Format(time.RFC3339) {
+		t.Errorf("expected fromTimestamp=%s, got %s", from.Format(time.RFC3339), q.Get("fromTimestamp"))
+	}
+	if q.Has("toTimestamp") {
+		t.Error("toTimestamp must be omitted when nil")
+	}
+}
@@ -1,12 +1,17 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/ezardev-team/langfuse-go/model"
 	"github.com/henomis/restclientgo"
+	coltrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
 type Response struct {
@@ -48,6 +53,113 @@ type ObservationsResponse struct {
 	Meta model.ObservationsCursorMeta `json:"meta"`
 }
 
+// TraceResponse is the response of `GET /api/public/traces/{traceId}`. The
+// embedded observations are not decoded; they are fetched through the v2
+// observations API so they share the ObservationView shape.
+type TraceResponse struct {
+	Response
+	Trace  model.TraceView
+	Scores []model.ScoreView
+}
+
+type TracesResponse struct {
+	Response
+	Data []model.TraceView `json:"data"`
+	Meta model.PageMeta    `json:"meta"`
+}
+
+// SessionResponse is the response of `GET /api/public/sessions/{sessionId}`.
+type SessionResponse struct {
+	Response
+	Session model.SessionView
+	Traces  []model.TraceView
+}
+
+type SessionsResponse struct {
+	Response
+	Data []model.SessionView `json:"data"`
+	Meta model.PageMeta      `json:"meta"`
+}
+
+// ScoreCreateResponse is the response of `POST /api/public/scores`.
+type ScoreCreateResponse struct {
+	Response
+	ID string `json:"id"`
+}
+
+type ScoreResponse struct {
+	Response
+	Score model.ScoreView
+}
+
+type ScoresResponse struct {
+	Response
+	Data []model.ScoreView `json:"data"`
+	Meta model.PageMeta    `json:"meta"`
+}
+
+type ScoreConfigResponse struct {
+	Response
+	Config model.ScoreConfig
+}
+
+type ScoreConfigsResponse struct {
+	Response
+	Data []model.ScoreConfig `json:"data"`
+	Meta model.PageMeta      `json:"meta"`
+}
+
+// DatasetResponse is the response of the dataset create and get endpoints.
+type DatasetResponse struct {
+	Response
+	Dataset model.Dataset
+}
+
+type DatasetsResponse struct {
+	Response
+	Data []model.Dataset `json:"data"`
+	Meta model.PageMeta  `json:"meta"`
+}
+
+// DatasetItemResponse is the response of the dataset item upsert and get
+// endpoints.
+type DatasetItemResponse struct {
+	Response
+	Item model.DatasetItem
+}
+
+type DatasetItemsResponse struct {
+	Response
+	Data []model.DatasetItem `json:"data"`
+	Meta model.PageMeta      `json:"meta"`
+}
+
+type DatasetRunItemResponse struct {
+	Response
+	RunItem model.DatasetRunItem
+}
+
+// MediaUploadResponse is the response of `POST /api/public/media`.
+// UploadURL is nil when the content is already stored.
+type MediaUploadResponse struct {
+	Response
+	MediaID   string  `json:"mediaId"`
+	UploadURL *string `json:"uploadUrl"`
+}
+
+// MediaPatchResponse is the response of `PATCH /api/public/media/{mediaId}`,
+// which has no body.
+type MediaPatchResponse struct {
+	Response
+}
+
+// MetricsResponse is the response of `GET /api/public/metrics`. Row values
+// are decoded with json.Number so large counts keep their precision.
+type MetricsResponse struct {
+	Response
+	Data []map[string]any `json:"data"`
+}
+
 func (r *Response) IsSuccess() bool {
 	return r.Code < http.StatusBadRequest
 }
@@ -77,13 +189,24 @@ func (r *Response) Decode(body io.Reader) error {
 	return json.NewDecoder(body).Decode(r)
 }
 
+func (r *MediaPatchResponse) AcceptContentType() string {
+	return ""
+}
+
 func (r *Response) SetHeaders(_ restclientgo.Headers) error {
 	return nil
 }
 
+// OpenTelemetryResponse is the response of the OTLP traces endpoint. A
+// successful export may still report rejected spans through the
+// ExportTraceServiceResponse partial_success field; those are decoded into
+// RejectedSpans and ErrorMessage.
 type OpenTelemetryResponse struct {
-	Code    int     `json:"-"`
-	RawBody *string `json:"-"`
+	Code          int     `json:"-"`
+	RawBody       *string `json:"-"`
+	ContentType   string  `json:"-"`
+	RejectedSpans int64   `json:"-"`
+	ErrorMessage  string  `json:"-"`
 }
 
 func (r *OpenTelemetryResponse) IsSuccess() bool {
@@ -104,9 +227,38 @@ func (r *OpenTelemetryResponse) SetBody(body io.Reader) error {
 	s := string(b)
 	r.RawBody = &s
 
+	if r.IsSuccess() {
+		r.decodePartialSuccess(b)
+	}
+
 	return nil
 }
 
+// decodePartialSuccess reads an ExportTraceServiceResponse encoded as
+// protobuf or JSON, following the response content type. Bodies that are
+// neither (e.g. an empty body) carry no partial success and are ignored.
+func (r *OpenTelemetryResponse) decodePartialSuccess(body []byte) {
+	if len(body) == 0 {
+		return
+	}
+
+	var export coltrace.ExportTraceServiceResponse
+	var err error
+	if strings.Contains(r.ContentType, "json") {
+		err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(body, &export)
+	} else {
+		err = proto.Unmarshal(body, &export)
+	}
+	if err != nil {
+		return
+	}
+
+	if partial := export.GetPartialSuccess(); partial != nil {
+		r.RejectedSpans = partial.GetRejectedSpans()
+		r.ErrorMessage = partial.GetErrorMessage()
+	}
+}
+
 func (r *OpenTelemetryResponse) AcceptContentType() string {
 	return ""
 }
@@ -122,10 +274,13 @@ func (r *OpenTelemetryResponse) Decode(body io.Reader) error {
 		r.RawBody = &bodyString
 	}
 
+	r.decodePartialSuccess(rawBody)
+
 	return nil
 }
 
-func (r *OpenTelemetryResponse) SetHeaders(_ restclientgo.Headers) error {
+func (r *OpenTelemetryResponse) SetHeaders(headers restclientgo.Headers) error {
+	r.ContentType = http.Header(headers).Get("Content-Type")
 	return nil
 }
 
@@ -143,6 +298,256 @@ func (r *ObservationsResponse) Decode(body io.Reader) error {
 	return json.Unmarshal(rawBody, r)
 }
 
+func (r *TraceResponse) Decode(body io.Reader) error {
+	rawBody, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	if r.RawBody == nil {
+		bodyString := string(rawBody)
+		r.RawBody = &bodyString
+	}
+
+	if err := json.Unmarshal(rawBody, &r.Trace); err != nil {
+		return err
+	}
+
+	var scores struct {
+		Scores []model.ScoreView `json:"scores"`
+	}
+	if err := json.Unmarshal(rawBody, &scores); err != nil {
+		return err
+	}
+	r.Scores = scores.Scores
+
+	return nil
+}
+
+func (r *TracesResponse) Decode(body io.Reader) error {
+	rawBody, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	if r.RawBody == nil {
+		bodyString := string(rawBody)
+		r.RawBody = &bodyString
+	}
+
+	return json.Unmarshal(rawBody, r)
+}
+
+func (r *SessionResponse) Decode(body io.Reader) error {
+	rawBody, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	if r.RawBody == nil {
+		bodyString := string(rawBody)
+		r.RawBody = &bodyString
+	}
+
+	if err := json.Unmarshal(rawBody, &r.Session); err != nil {
+		return err
+	}
+
+	var traces struct {
+		Traces []model.TraceView `json:"traces"`
+	}
+	if err := json.Unmarshal(rawBody, &traces); err != nil {
+		return err
+	}
+	r.Traces = traces.Traces
+
+	return nil
+}
+
+func (r *SessionsResponse) Decode(body io.Reader) error {
+	rawBody, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	if r.RawBody == nil {
+		bodyString := string(rawBody)
+		r.RawBody = &bodyString
+	}
+
+	return json.Unmarshal(rawBody, r)
+}
+
+func (r *MediaUploadResponse) Decode(body io.Reader) error {
+	rawBody, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	if r.RawBody == nil {
+		bodyString := string(rawBody)
+		r.RawBody = &bodyString
+	}
+
+	return json.Unmarshal(rawBody, r)
+}
+
+func (r *ScoreCreateResponse) Decode(body io.Reader) error {
+	rawBody, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	if r.RawBody == nil {
+		bodyString := string(rawBody)
+		r.RawBody = &bodyString
+	}
+
+	return json.Unmarshal(rawBody, r)
+}
+
+func (r *ScoreResponse) Decode(body io.Reader) error {
+	rawBody, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	if r.RawBody == nil {
+		bodyString := string(rawBody)
+		r.RawBody = &bodyString
+	}
+
+	return json.Unmarshal(rawBody, &r.Score)
+}
+
+func (r *ScoresResponse) Decode(body io.Reader) error {
+	rawBody, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	if r.RawBody == nil {
+		bodyString := string(rawBody)
+		r.RawBody = &bodyString
+	}
+
+	return json.Unmarshal(rawBody, r)
+}
+
+func (r *ScoreConfigResponse) Decode(body io.Reader) error {
+	rawBody, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	if r.RawBody == nil {
+		bodyString := string(rawBody)
+		r.RawBody = &bodyString
+	}
+
+	return json.Unmarshal(rawBody, &r.Config)
+}
+
+func (r *ScoreConfigsResponse) Decode(body io.Reader) error {
+	rawBody, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	if r.RawBody == nil {
+		bodyString := string(rawBody)
+		r.RawBody = &bodyString
+	}
+
+	return json.Unmarshal(rawBody, r)
+}
+
+func (r *DatasetResponse) Decode(body io.Reader) error {
+	rawBody, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	if r.RawBody == nil {
+		bodyString := string(rawBody)
+		r.RawBody = &bodyString
+	}
+
+	return json.Unmarshal(rawBody, &r.Dataset)
+}
+
+func (r *DatasetsResponse) Decode(body io.Reader) error {
+	rawBody, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	if r.RawBody == nil {
+		bodyString := string(rawBody)
+		r.RawBody = &bodyString
+	}
+
+	return json.Unmarshal(rawBody, r)
+}
+
+func (r *DatasetItemResponse) Decode(body io.Reader) error {
+	rawBody, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	if r.RawBody == nil {
+		bodyString := string(rawBody)
+		r.RawBody = &bodyString
+	}
+
+	return json.Unmarshal(rawBody, &r.Item)
+}
+
+func (r *DatasetItemsResponse) Decode(body io.Reader) error {
+	rawBody, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	if r.RawBody == nil {
+		bodyString := string(rawBody)
+		r.RawBody = &bodyString
+	}
+
+	return json.Unmarshal(rawBody, r)
+}
+
+func (r *DatasetRunItemResponse) Decode(body io.Reader) error {
+	rawBody, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	if r.RawBody == nil {
+		bodyString := string(rawBody)
+		r.RawBody = &bodyString
+	}
+
+	return json.Unmarshal(rawBody, &r.RunItem)
+}
+
+func (r *MetricsResponse) Decode(body io.Reader) error {
+	rawBody, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	if r.RawBody == nil {
+		bodyString := string(rawBody)
+		r.RawBody = &bodyString
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(rawBody))
+	decoder.UseNumber()
+	return decoder.Decode(r)
+}
+
 func (r *PromptResponse) IsSuccess() bool {
 	return r.Code < http.StatusBadRequest
 }
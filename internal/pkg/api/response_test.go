@@ -6,6 +6,10 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+
+	"github.com/henomis/restclientgo"
+	coltrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
 )
 
 // --- Response tests ---
@@ -197,6 +201,57 @@ func TestOpenTelemetryResponse_SetHeaders(t *testing.T) {
 	}
 }
 
+func TestOpenTelemetryResponse_PartialSuccess_Protobuf(t *testing.T) {
+	body, err := proto.Marshal(&coltrace.ExportTraceServiceResponse{
+		PartialSuccess: &coltrace.ExportTracePartialSuccess{
+			RejectedSpans: 2,
+			ErrorMessage:  "span name is required",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &OpenTelemetryResponse{}
+	_ = r.SetHeaders(restclientgo.Headers{"Content-Type": {ContentTypeProtobuf}})
+	_ = r.SetStatusCode(http.StatusOK)
+	if err := r.SetBody(bytes.NewReader(body)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if r.RejectedSpans != 2 || r.ErrorMessage != "span name is required" {
+		t.Errorf("rejected=%d message=%q", r.RejectedSpans, r.ErrorMessage)
+	}
+}
+
+func TestOpenTelemetryResponse_PartialSuccess_JSON(t *testing.T) {
+	r := &OpenTelemetryResponse{}
+	_ = r.SetHeaders(restclientgo.Headers{"Content-Type": {"application/json; charset=utf-8"}})
+	_ = r.SetStatusCode(http.StatusOK)
+	body := `{"partialSuccess":{"rejectedSpans":"3","errorMessage":"invalid trace id"}}`
+	if err := r.SetBody(strings.NewReader(body)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if r.RejectedSpans != 3 || r.ErrorMessage != "invalid trace id" {
+		t.Errorf("rejected=%d message=%q", r.RejectedSpans, r.ErrorMessage)
+	}
+}
+
+func TestOpenTelemetryResponse_PartialSuccess_Absent(t *testing.T) {
+	for _, body := range []string{``, `{}`, `not json`} {
+		r := &OpenTelemetryResponse{}
+		_ = r.SetHeaders(restclientgo.Headers{"Content-Type": {"application/json"}})
+		_ = r.SetStatusCode(http.StatusOK)
+		if err := r.SetBody(strings.NewReader(body)); err != nil {
+			t.Fatalf("%q: unexpected error: %v", body, err)
+		}
+		if r.RejectedSpans != 0 || r.ErrorMessage != "" {
+			t.Errorf("%q: rejected=%d message=%q", body, r.RejectedSpans, r.ErrorMessage)
+		}
+	}
+}
+
 // --- PromptResponse tests ---
 
 func TestPromptResponse_IsSuccess(t *testing.T) {
@@ -460,3 +515,46 @@ func TestObservationsResponse_Decode_InvalidJSON(t *testing.T) {
 		t.Fatal("expected error for invalid JSON")
 	}
 }
+
+func TestTraceResponse_Decode(t *testing.T) {
+	jsonBody := `{
+		"id": "trace-1",
+		"name": "checkout",
+		"tags": ["prod"],
+		"latency": 2.5,
+		"observations": [{"id": "obs-1"}],
+		"scores": [{"id": "score-1", "name": "quality", "value": 1, "stringValue": "good"}]
+	}`
+
+	r := &TraceResponse{}
+	if err := r.Decode(strings.NewReader(jsonBody)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Trace.ID != "trace-1" || r.Trace.Name != "checkout" || r.Trace.Latency != 2.5 {
+		t.Errorf("unexpected trace: %+v", r.Trace)
+	}
+	if len(r.Scores) != 1 || r.Scores[0].StringValue != "good" {
+		t.Errorf("unexpected scores: %+v", r.Scores)
+	}
+	if r.RawBody == nil {
+		t.Error("expected RawBody to be set")
+	}
+}
+
+func TestTracesResponse_Decode(t *testing.T) {
+	jsonBody := `{
+		"data": [{"id": "trace-1", "scores": ["score-1"], "observations": ["obs-1"]}],
+		"meta": {"page": 1, "limit": 50, "totalItems": 1, "totalPages": 1}
+	}`
+
+	r := &TracesResponse{}
+	if err := r.Decode(strings.NewReader(jsonBody)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(r.Data) != 1 || r.Data[0].ID != "trace-1" {
+		t.Errorf("unexpected data: %+v", r.Data)
+	}
+	if r.Meta.TotalPages != 1 || r.Meta.Limit != 50 {
+		t.Errorf("unexpected meta: %+v", r.Meta)
+	}
+}
@@ -2,6 +2,7 @@ package otel
 
 import (
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -11,8 +12,8 @@ import (
 	"github.com/ezardev-team/langfuse-go/model"
 	coltrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
 	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
-	resourcev1 "go.opentelemetry.io/proto/otlp/resource/v1"
 	tracev1 "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -39,6 +40,7 @@ type observationState struct {
 	completionStartTime *time.Time
 	level               model.ObservationLevel
 	statusMessage       string
+	environment         string
 	input               any
 	output              any
 	metadata            any
@@ -50,17 +52,114 @@ type observationState struct {
 	promptVersion       int
 	hasPromptVersion    bool
 	public              *bool
+	errors              []model.ObservationError
 }
 
 type traceContext struct {
 	traceID        string
 	traceIDBytes   []byte
 	rootSpanID     []byte
+	environment    string
 	propagateAttrs []*commonv1.KeyValue
 	rootAttrs      []*commonv1.KeyValue
 }
 
 func EncodeEvents(events []model.IngestionEvent) ([]byte, error) {
+	return Encoder{}.Encode(events)
+}
+
+// EncodeEventsJSON encodes events as OTLP/JSON with the default resource.
+func EncodeEventsJSON(events []model.IngestionEvent) ([]byte, error) {
+	return Encoder{}.EncodeJSON(events)
+}
+
+// Encoder encodes ingestion events as OTLP export requests.
+type Encoder struct {
+	// Resource is attached to every export request.
+	Resource Resource
+	// Environment is recorded on every span whose observation or trace does
+	// not set its own.
+	Environment string
+	// MetadataLimits bounds the attributes each metadata value expands into.
+	MetadataLimits MetadataLimits
+	// SizeLimits bounds the size of inputs, outputs and spans.
+	SizeLimits SizeLimits
+	// OnTruncate is called for every payload shortened to fit SizeLimits.
+	OnTruncate func()
+}
+
+// Encode encodes events as a binary OTLP ExportTraceServiceRequest.
+func (e Encoder) Encode(events []model.IngestionEvent) ([]byte, error) {
+	request, err := e.buildExportRequest(events)
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Marshal(request)
+}
+
+// EncodeJSON encodes events as an OTLP/JSON ExportTraceServiceRequest. It
+// follows the OTLP/JSON rules that differ from the canonical protobuf JSON
+// mapping: trace and span IDs are hex strings and enums are integers.
+func (e Encoder) EncodeJSON(events []model.IngestionEvent) ([]byte, error) {
+	request, err := e.buildExportRequest(events)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := protojson.MarshalOptions{UseEnumNumbers: true}.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	if err := hexEncodeIDs(decoded); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(decoded)
+}
+
+// otlpIDFields are the OTLP/JSON fields carrying trace or span IDs.
+var otlpIDFields = []string{"traceId", "spanId", "parentSpanId"}
+
+// hexEncodeIDs rewrites the base64 IDs produced by protojson as hex, walking
+// spans and their links and events.
+func hexEncodeIDs(value any) error {
+	switch v := value.(type) {
+	case map[string]any:
+		for _, field := range otlpIDFields {
+			encoded, ok := v[field].(string)
+			if !ok {
+				continue
+			}
+			id, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return fmt.Errorf("decode %s: %w", field, err)
+			}
+			v[field] = hex.EncodeToString(id)
+		}
+		for _, child := range v {
+			if err := hexEncodeIDs(child); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, child := range v {
+			if err := hexEncodeIDs(child); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// buildExportRequest folds events into one span per observation.
+func (e Encoder) buildExportRequest(events []model.IngestionEvent) (*coltrace.ExportTraceServiceRequest, error) {
 	traceContexts := map[string]*traceContext{}
 	for _, event := range events {
 		if event.Type != model.IngestionEventTypeTraceCreate {
@@ -77,7 +176,7 @@ func EncodeEvents(events []model.IngestionEvent) ([]byte, error) {
 		if traceID == "" {
 			traceID = uuidFallback(event.Timestamp)
 		}
-		traceContexts[traceID] = buildTraceContext(traceID, trace)
+		traceContexts[traceID] = e.buildTraceContext(traceID, trace)
 	}
 
 	observations := map[string]*observationState{}
@@ -141,7 +240,7 @@ func EncodeEvents(events []model.IngestionEvent) ([]byte, error) {
 
 	spans := make([]*tracev1.Span, 0, len(observations))
 	for _, obs := range observations {
-		span, err := buildSpan(obs, traceContexts[obs.traceID])
+		span, err := e.buildSpan(obs, traceContexts[obs.traceID])
 		if err != nil {
 			return nil, err
 		}
@@ -151,15 +250,12 @@ func EncodeEvents(events []model.IngestionEvent) ([]byte, error) {
 	request := &coltrace.ExportTraceServiceRequest{
 		ResourceSpans: []*tracev1.ResourceSpans{
 			{
-				Resource: &resourcev1.Resource{
-					Attributes: []*commonv1.KeyValue{
-						attrString("service.name", instrumentationName),
-					},
-				},
+				Resource: e.Resource.proto(),
 				ScopeSpans: []*tracev1.ScopeSpans{
 					{
 						Scope: &commonv1.InstrumentationScope{
-							Name: instrumentationName,
+							Name:    instrumentationName,
+							Version: sdkVersion,
 						},
 						Spans: spans,
 					},
@@ -168,10 +264,10 @@ func EncodeEvents(events []model.IngestionEvent) ([]byte, error) {
 		},
 	}
 
-	return proto.Marshal(request)
+	return request, nil
 }
 
-func buildTraceContext(traceID string, trace *model.Trace) *traceContext {
+func (e Encoder) buildTraceContext(traceID string, trace *model.Trace) *traceContext {
 	propagate := make([]*commonv1.KeyValue, 0)
 	rootAttrs := make([]*commonv1.KeyValue, 0)
 
@@ -191,16 +287,16 @@ func buildTraceContext(traceID string, trace *model.Trace) *traceContext {
 		propagate = append(propagate, attrStringArray("langfuse.trace.tags", trace.Tags))
 	}
 
-	propagate = appendMetadataAttrs("langfuse.trace.metadata.", trace.Metadata, propagate)
+	propagate = appendMetadataAttrs("langfuse.trace.metadata.", trace.Metadata, propagate, e.MetadataLimits)
 
 	if trace.Name != "" {
 		rootAttrs = append(rootAttrs, attrString("langfuse.trace.name", trace.Name))
 	}
 	if trace.Input != nil {
-		rootAttrs = append(rootAttrs, attrString("langfuse.trace.input", jsonString(trace.Input)))
+		rootAttrs = append(rootAttrs, e.payloadAttrs("langfuse.trace.input", trace.Input)...)
 	}
 	if trace.Output != nil {
-		rootAttrs = append(rootAttrs, attrString("langfuse.trace.output", jsonString(trace.Output)))
+		rootAttrs = append(rootAttrs, e.payloadAttrs("langfuse.trace.output", trace.Output)...)
 	}
 	rootAttrs = append(rootAttrs, attrBool("langfuse.trace.public", trace.Public))
 
@@ -208,12 +304,16 @@ func buildTraceContext(traceID string, trace *model.Trace) *traceContext {
 		traceID:        traceID,
 		traceIDBytes:   traceIDFromString(traceID),
 		rootSpanID:     spanIDFromString(traceID),
+		environment:    coalesce(trace.Environment, e.Environment),
 		propagateAttrs: propagate,
 		rootAttrs:      rootAttrs,
 	}
 }
 
-func buildSpan(state *observationState, traceCtx *traceContext) (*tracev1.Span, error) {
+// buildSpan builds the span of one observation. Its environment is the one
+// it carries, else its trace's when the trace is part of the batch, else the
+// encoder's.
+func (e Encoder) buildSpan(state *observationState, traceCtx *traceContext) (*tracev1.Span, error) {
 	traceID := state.traceID
 	if traceID == "" && traceCtx != nil {
 		traceID = traceCtx.traceID
@@ -250,7 +350,14 @@ func buildSpan(state *observationState, traceCtx *traceContext) (*tracev1.Span,
 		endTime = startTime
 	}
 
+	env := e.Environment
+	if traceCtx != nil {
+		env = traceCtx.environment
+	}
 	attrs := make([]*commonv1.KeyValue, 0)
+	if env = coalesce(state.environment, env); env != "" {
+		attrs = append(attrs, attrString("langfuse.environment", env))
+	}
 	if traceCtx != nil {
 		attrs = append(attrs, traceCtx.propagateAttrs...)
 	}
@@ -258,7 +365,8 @@ func buildSpan(state *observationState, traceCtx *traceContext) (*tracev1.Span,
 		attrs = append(attrs, traceCtx.rootAttrs...)
 	}
 
-	attrs = append(attrs, observationAttributes(state)...)
+	attrs = append(attrs, e.observationAttributes(state)...)
+	attrs = e.limitSpanSize(attrs)
 
 	span := &tracev1.Span{
 		TraceId:           traceIDBytes,
@@ -270,16 +378,46 @@ func buildSpan(state *observationState, traceCtx *traceContext) (*tracev1.Span,
 		Attributes:        attrs,
 	}
 
-	if state.statusMessage != "" {
+	if state.level == model.ObservationLevelError || state.statusMessage != "" {
 		span.Status = &tracev1.Status{
 			Message: state.statusMessage,
 		}
+		if state.level == model.ObservationLevelError {
+			span.Status.Code = tracev1.Status_STATUS_CODE_ERROR
+		}
+	}
+
+	for _, obsErr := range state.errors {
+		span.Events = append(span.Events, exceptionEvent(obsErr, *endTime))
 	}
 
 	return span, nil
 }
 
-func observationAttributes(state *observationState) []*commonv1.KeyValue {
+// exceptionEvent builds an OTLP exception event following the OpenTelemetry
+// semantic conventions.
+func exceptionEvent(obsErr model.ObservationError, fallback time.Time) *tracev1.Span_Event {
+	at := fallback
+	if obsErr.Time != nil {
+		at = *obsErr.Time
+	}
+
+	attrs := []*commonv1.KeyValue{
+		attrString("exception.type", obsErr.Type),
+		attrString("exception.message", obsErr.Message),
+	}
+	if obsErr.Stacktrace != "" {
+		attrs = append(attrs, attrString("exception.stacktrace", obsErr.Stacktrace))
+	}
+
+	return &tracev1.Span_Event{
+		Name:         "exception",
+		TimeUnixNano: uint64(at.UnixNano()),
+		Attributes:   attrs,
+	}
+}
+
+func (e Encoder) observationAttributes(state *observationState) []*commonv1.KeyValue {
 	attrs := make([]*commonv1.KeyValue, 0)
 	obsType := observationType(state.kind)
 	if obsType != "" {
@@ -292,13 +430,13 @@ func observationAttributes(state *observationState) []*commonv1.KeyValue {
 		attrs = append(attrs, attrString("langfuse.observation.status_message", state.statusMessage))
 	}
 	if state.input != nil {
-		attrs = append(attrs, attrString("langfuse.observation.input", jsonString(state.input)))
+		attrs = append(attrs, e.payloadAttrs("langfuse.observation.input", state.input)...)
 	}
 	if state.output != nil {
-		attrs = append(attrs, attrString("langfuse.observation.output", jsonString(state.output)))
+		attrs = append(attrs, e.payloadAttrs("langfuse.observation.output", state.output)...)
 	}
 
-	attrs = appendMetadataAttrs("langfuse.observation.metadata.", state.metadata, attrs)
+	attrs = appendMetadataAttrs("langfuse.observation.metadata.", state.metadata, attrs, e.MetadataLimits)
 
 	if state.model != "" {
 		attrs = append(attrs, attrString("langfuse.observation.model.name", state.model))
@@ -364,7 +502,11 @@ func applyGenerationCreate(observations map[string]*observationState, gen *model
 		state.level = gen.Level
 	}
 	state.statusMessage = coalesce(state.statusMessage, gen.StatusMessage)
+	state.environment = coalesce(state.environment, gen.Environment)
 	state.version = coalesce(state.version, gen.Version)
+	if len(state.errors) == 0 {
+		state.errors = gen.Errors
+	}
 	state.model = coalesce(state.model, gen.Model)
 	state.modelParameters = coalesceAny(state.modelParameters, gen.ModelParameters)
 	if isZeroUsage(state.usage) {
@@ -400,9 +542,15 @@ func applyGenerationUpdate(observations map[string]*observationState, gen *model
 	if gen.StatusMessage != "" {
 		state.statusMessage = gen.StatusMessage
 	}
+	if gen.Environment != "" {
+		state.environment = gen.Environment
+	}
 	if gen.Version != "" {
 		state.version = gen.Version
 	}
+	if len(gen.Errors) > 0 {
+		state.errors = gen.Errors
+	}
 	if gen.Model != "" {
 		state.model = gen.Model
 	}
@@ -442,7 +590,11 @@ func applySpanCreate(observations map[string]*observationState, span *model.Span
 		state.level = span.Level
 	}
 	state.statusMessage = coalesce(state.statusMessage, span.StatusMessage)
+	state.environment = coalesce(state.environment, span.Environment)
 	state.version = coalesce(state.version, span.Version)
+	if len(state.errors) == 0 {
+		state.errors = span.Errors
+	}
 }
 
 func applySpanUpdate(observations map[string]*observationState, span *model.Span, fallback time.Time) {
@@ -467,9 +619,15 @@ func applySpanUpdate(observations map[string]*observationState, span *model.Span
 	if span.StatusMessage != "" {
 		state.statusMessage = span.StatusMessage
 	}
+	if span.Environment != "" {
+		state.environment = span.Environment
+	}
 	if span.Version != "" {
 		state.version = span.Version
 	}
+	if len(span.Errors) > 0 {
+		state.errors = span.Errors
+	}
 }
 
 func applyEventCreate(observations map[string]*observationState, ev *model.Event, fallback time.Time) {
@@ -488,7 +646,11 @@ func applyEventCreate(observations map[string]*observationState, ev *model.Event
 		state.level = ev.Level
 	}
 	state.statusMessage = coalesce(state.statusMessage, ev.StatusMessage)
+	state.environment = coalesce(state.environment, ev.Environment)
 	state.version = coalesce(state.version, ev.Version)
+	if len(state.errors) == 0 {
+		state.errors = ev.Errors
+	}
 }
 
 func getOrCreateObservation(observations map[string]*observationState, key string, kind observationKind, fallback time.Time) *observationState {
@@ -539,6 +701,43 @@ func attrInt(key string, value int64) *commonv1.KeyValue {
 	}
 }
 
+func attrDouble(key string, value float64) *commonv1.KeyValue {
+	if key == "" {
+		return nil
+	}
+	return &commonv1.KeyValue{
+		Key: key,
+		Value: &commonv1.AnyValue{
+			Value: &commonv1.AnyValue_DoubleValue{DoubleValue: value},
+		},
+	}
+}
+
+// attrAny maps common Go values to their OTLP attribute type and
+// JSON-encodes anything else.
+func attrAny(key string, value any) *commonv1.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attrString(key, v)
+	case bool:
+		return attrBool(key, v)
+	case int:
+		return attrInt(key, int64(v))
+	case int32:
+		return attrInt(key, int64(v))
+	case int64:
+		return attrInt(key, v)
+	case float32:
+		return attrDouble(key, float64(v))
+	case float64:
+		return attrDouble(key, v)
+	case []string:
+		return attrStringArray(key, v)
+	default:
+		return attrString(key, jsonString(v))
+	}
+}
+
 func attrStringArray(key string, values []string) *commonv1.KeyValue {
 	if key == "" || len(values) == 0 {
 		return nil
@@ -559,45 +758,6 @@ func attrStringArray(key string, values []string) *commonv1.KeyValue {
 	}
 }
 
-func appendMetadataAttrs(prefix string, metadata any, attrs []*commonv1.KeyValue) []*commonv1.KeyValue {
-	if metadata == nil {
-		return attrs
-	}
-
-	switch m := metadata.(type) {
-	case map[string]string:
-		for key, value := range m {
-			if key == "" {
-				continue
-			}
-			attrs = append(attrs, attrString(prefix+key, value))
-		}
-	case map[string]any:
-		for key, value := range m {
-			if key == "" {
-				continue
-			}
-			attrs = append(attrs, attrString(prefix+key, metadataValueString(value)))
-		}
-	default:
-		attrs = append(attrs, attrString(prefix+"raw", jsonString(metadata)))
-	}
-
-	return attrs
-}
-
-// metadataValueString renders a metadata value for an OTEL string attribute.
-// String values are stored verbatim: json.Marshal would wrap them in literal
-// double quotes (e.g. cache_key "abc" -> `"abc"`), which then get persisted on
-// the Langfuse side and break exact-match metadata filters and cache lookups.
-// Non-string values still fall back to their JSON encoding.
-func metadataValueString(value any) string {
-	if s, ok := value.(string); ok {
-		return s
-	}
-	return jsonString(value)
-}
-
 func jsonString(value any) string {
 	if value == nil {
 		return ""
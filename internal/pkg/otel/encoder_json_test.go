@@ -0,0 +1,68 @@
+package otel
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+// TestEncodeEventsJSON verifies the OTLP/JSON encoding uses hex IDs and
+// integer enums instead of the protobuf JSON mapping defaults.
+func TestEncodeEventsJSON(t *testing.T) {
+	now := time.Now()
+	traceID := "0123456789abcdef0123456789abcdef"
+	events := []model.IngestionEvent{
+		{Type: model.IngestionEventTypeTraceCreate, Timestamp: now, Body: &model.Trace{ID: traceID, Name: "trace"}},
+		{Type: model.IngestionEventTypeSpanCreate, Timestamp: now, Body: &model.Span{
+			ID: "parent", TraceID: traceID, Name: "parent", StartTime: &now,
+		}},
+		{Type: model.IngestionEventTypeSpanCreate, Timestamp: now, Body: &model.Span{
+			ID: "child", TraceID: traceID, Name: "child", StartTime: &now, ParentObservationID: "parent",
+		}},
+	}
+
+	data, err := EncodeEventsJSON(events)
+	if err != nil {
+		t.Fatalf("EncodeEventsJSON: %v", err)
+	}
+
+	var decoded struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []struct {
+					Name         string `json:"name"`
+					TraceID      string `json:"traceId"`
+					SpanID       string `json:"spanId"`
+					ParentSpanID string `json:"parentSpanId"`
+					Kind         any    `json:"kind"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	hexID := regexp.MustCompile(`^[0-9a-f]+$`)
+	spans := decoded.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 3 {
+		t.Fatalf("spans=%d, want 3", len(spans))
+	}
+	for _, span := range spans {
+		if span.TraceID != traceID {
+			t.Errorf("%s: traceId=%q, want %q", span.Name, span.TraceID, traceID)
+		}
+		if len(span.SpanID) != 16 || !hexID.MatchString(span.SpanID) {
+			t.Errorf("%s: spanId=%q, want 16 hex chars", span.Name, span.SpanID)
+		}
+		if _, ok := span.Kind.(string); ok {
+			t.Errorf("%s: kind=%v, want an integer", span.Name, span.Kind)
+		}
+		if span.Name == "child" && (len(span.ParentSpanID) != 16 || !hexID.MatchString(span.ParentSpanID)) {
+			t.Errorf("child: parentSpanId=%q, want 16 hex chars", span.ParentSpanID)
+		}
+	}
+}
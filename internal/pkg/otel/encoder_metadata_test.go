@@ -2,7 +2,9 @@ package otel
 
 import (
 	"testing"
+	"time"
 
+	"github.com/ezardev-team/langfuse-go/model"
 	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
 )
 
@@ -16,6 +18,16 @@ func attrValue(attrs []*commonv1.KeyValue, key string) (string, bool) {
 	return "", false
 }
 
+// attrAnyValue looks up the value of an OTEL attribute by key.
+func attrAnyValue(attrs []*commonv1.KeyValue, key string) *commonv1.AnyValue {
+	for _, kv := range attrs {
+		if kv != nil && kv.Key == key {
+			return kv.Value
+		}
+	}
+	return nil
+}
+
 // TestAppendMetadataAttrs_StringNotQuoted verifies that string metadata values
 // are stored verbatim (not JSON-quoted). Wrapping them in literal double quotes
 // is what previously broke exact-match metadata filters and cache_key lookups
@@ -27,16 +39,94 @@ func TestAppendMetadataAttrs_StringNotQuoted(t *testing.T) {
 		"obj":       map[string]any{"a": 1},
 	}
 
-	attrs := appendMetadataAttrs("p.", meta, nil)
+	attrs := appendMetadataAttrs("p.", meta, nil, MetadataLimits{})
 
 	if got, ok := attrValue(attrs, "p.cache_key"); !ok || got != "abc123" {
 		t.Errorf("cache_key=%q (ok=%v), want \"abc123\" with no surrounding quotes", got, ok)
 	}
-	// Non-string values keep their JSON encoding.
-	if got, ok := attrValue(attrs, "p.count"); !ok || got != "5" {
-		t.Errorf("count=%q (ok=%v), want \"5\"", got, ok)
+	// Numbers keep their native type and nested maps are flattened.
+	if got := attrAnyValue(attrs, "p.count"); got.GetIntValue() != 5 {
+		t.Errorf("count=%v, want int 5", got)
+	}
+	if got := attrAnyValue(attrs, "p.obj.a"); got.GetIntValue() != 1 {
+		t.Errorf("obj.a=%v, want int 1", got)
+	}
+}
+
+// TestAppendMetadataAttrs_NativeTypes verifies scalars and scalar arrays keep
+// their OTLP type, for model.M and for structs encoded through their JSON tags.
+func TestAppendMetadataAttrs_NativeTypes(t *testing.T) {
+	type request struct {
+		Temperature float64  `json:"temperature"`
+		Stream      bool     `json:"stream"`
+		Stop        []string `json:"stop"`
+		Tenant      struct {
+			ID int `json:"id"`
+		} `json:"tenant"`
+	}
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for name, meta := range map[string]any{
+		"model.M": model.M{
+			"temperature": 0.2, "stream": true, "stop": []string{"\n"},
+			"tenant": model.M{"id": 7}, "at": at, "scores": []float64{0.5, 1},
+		},
+		"struct": &request{Temperature: 0.2, Stream: true, Stop: []string{"\n"}, Tenant: struct {
+			ID int `json:"id"`
+		}{ID: 7}},
+	} {
+		attrs := appendMetadataAttrs("p.", meta, nil, MetadataLimits{})
+
+		if got := attrAnyValue(attrs, "p.temperature"); got.GetDoubleValue() != 0.2 {
+			t.Errorf("%s: temperature=%v, want double", name, got)
+		}
+		if got := attrAnyValue(attrs, "p.stream"); !got.GetBoolValue() {
+			t.Errorf("%s: stream=%v, want bool", name, got)
+		}
+		if got := attrAnyValue(attrs, "p.stop").GetArrayValue(); len(got.GetValues()) != 1 || got.Values[0].GetStringValue() != "\n" {
+			t.Errorf("%s: stop=%v, want string array", name, got)
+		}
+		if got := attrAnyValue(attrs, "p.tenant.id"); got.GetIntValue() != 7 {
+			t.Errorf("%s: tenant.id=%v, want int 7", name, got)
+		}
+		if name == "model.M" {
+			if got, _ := attrValue(attrs, "p.at"); got != "2026-01-02T03:04:05Z" {
+				t.Errorf("at=%q, want RFC 3339 without quotes", got)
+			}
+			if got := attrAnyValue(attrs, "p.scores").GetArrayValue(); len(got.GetValues()) != 2 || got.Values[1].GetDoubleValue() != 1 {
+				t.Errorf("scores=%v, want double array", got)
+			}
+		}
+	}
+}
+
+// TestAppendMetadataAttrs_Limits verifies MaxDepth stores deeper maps as JSON
+// and MaxKeys drops the keys past the limit in sorted order.
+func TestAppendMetadataAttrs_Limits(t *testing.T) {
+	meta := map[string]any{
+		"a": map[string]any{"b": map[string]any{"c": 1}},
+		"x": 1,
+		"y": []map[string]any{{"k": "v"}},
+		"z": 3,
+	}
+
+	attrs := appendMetadataAttrs("p.", meta, nil, MetadataLimits{MaxKeys: 2, MaxDepth: 2})
+
+	if len(attrs) != 2 {
+		t.Fatalf("attrs=%d, want 2", len(attrs))
+	}
+	if got, _ := attrValue(attrs, "p.a.b"); got != `{"c":1}` {
+		t.Errorf("a.b=%q, want JSON past MaxDepth", got)
+	}
+	if got := attrAnyValue(attrs, "p.x"); got.GetIntValue() != 1 {
+		t.Errorf("x=%v", got)
+	}
+
+	attrs = appendMetadataAttrs("p.", meta, nil, MetadataLimits{})
+	if got, _ := attrValue(attrs, "p.y"); got != `[{"k":"v"}]` {
+		t.Errorf("y=%q, want JSON for arrays of objects", got)
 	}
-	if got, ok := attrValue(attrs, "p.obj"); !ok || got != `{"a":1}` {
-		t.Errorf("obj=%q (ok=%v), want {\"a\":1}", got, ok)
+	if got, _ := attrValue(appendMetadataAttrs("p.", []string{"a"}, nil, MetadataLimits{}), "p.raw"); got != `["a"]` {
+		t.Errorf("raw=%q, want JSON for non-object metadata", got)
 	}
 }
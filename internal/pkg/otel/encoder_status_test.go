@@ -0,0 +1,106 @@
+package otel
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/ezardev-team/langfuse-go/model"
+	coltrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracev1 "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func encodeSpans(t *testing.T, events []model.IngestionEvent) map[string]*tracev1.Span {
+	t.Helper()
+	data, err := EncodeEvents(events)
+	if err != nil {
+		t.Fatalf("EncodeEvents: %v", err)
+	}
+	return decodeSpans(t, data)
+}
+
+func decodeSpans(t *testing.T, data []byte) map[string]*tracev1.Span {
+	t.Helper()
+	var req coltrace.ExportTraceServiceRequest
+	if err := proto.Unmarshal(data, &req); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	spans := map[string]*tracev1.Span{}
+	for _, span := range req.ResourceSpans[0].ScopeSpans[0].Spans {
+		spans[span.Name] = span
+	}
+	return spans
+}
+
+// TestSpanStatus verifies ERROR level maps to STATUS_CODE_ERROR while other
+// levels keep the status unset.
+func TestSpanStatus(t *testing.T) {
+	now := time.Now()
+	spans := encodeSpans(t, []model.IngestionEvent{
+		{Type: model.IngestionEventTypeGenerationCreate, Timestamp: now, Body: &model.Generation{
+			ID: "g", TraceID: "t", Name: "failed", Level: model.ObservationLevelError, StatusMessage: "rate limited",
+		}},
+		{Type: model.IngestionEventTypeSpanCreate, Timestamp: now, Body: &model.Span{
+			ID: "s", TraceID: "t", Name: "warned", Level: model.ObservationLevelWarning, StatusMessage: "slow",
+		}},
+		{Type: model.IngestionEventTypeSpanCreate, Timestamp: now, Body: &model.Span{
+			ID: "ok", TraceID: "t", Name: "fine",
+		}},
+	})
+
+	if got := spans["failed"].GetStatus(); got.GetCode() != tracev1.Status_STATUS_CODE_ERROR || got.GetMessage() != "rate limited" {
+		t.Errorf("failed status=%v, want ERROR with message", got)
+	}
+	if got := spans["warned"].GetStatus(); got.GetCode() != tracev1.Status_STATUS_CODE_UNSET || got.GetMessage() != "slow" {
+		t.Errorf("warned status=%v, want UNSET with message", got)
+	}
+	if spans["fine"].GetStatus() != nil {
+		t.Errorf("fine status=%v, want none", spans["fine"].GetStatus())
+	}
+}
+
+// TestExceptionEvents verifies recorded errors become exception events and
+// that the error recorded on create is not duplicated by the end update.
+func TestExceptionEvents(t *testing.T) {
+	now := time.Now()
+	span := &model.Span{ID: "s", TraceID: "t", Name: "read-config", StartTime: &now}
+	span.RecordError(&fs.PathError{Op: "open", Path: "config.yaml", Err: fs.ErrNotExist})
+
+	withStack := model.NewObservationError(errors.New("boom"), true)
+	gen := &model.Generation{ID: "g", TraceID: "t", Name: "call", Errors: []model.ObservationError{withStack}}
+
+	spans := encodeSpans(t, []model.IngestionEvent{
+		{Type: model.IngestionEventTypeSpanCreate, Timestamp: now, Body: span},
+		{Type: model.IngestionEventTypeSpanUpdate, Timestamp: now, Body: span},
+		{Type: model.IngestionEventTypeGenerationCreate, Timestamp: now, Body: gen},
+	})
+
+	got := spans["read-config"]
+	if got.GetStatus().GetCode() != tracev1.Status_STATUS_CODE_ERROR {
+		t.Errorf("status=%v, want ERROR", got.GetStatus())
+	}
+	if len(got.Events) != 1 {
+		t.Fatalf("events=%d, want 1", len(got.Events))
+	}
+	event := got.Events[0]
+	attrs := map[string]string{}
+	for _, kv := range event.Attributes {
+		attrs[kv.Key] = kv.Value.GetStringValue()
+	}
+	if event.Name != "exception" || attrs["exception.type"] != "*fs.PathError" || attrs["exception.message"] != "open config.yaml: file does not exist" {
+		t.Errorf("event=%s attrs=%v", event.Name, attrs)
+	}
+	if _, ok := attrs["exception.stacktrace"]; ok {
+		t.Error("stacktrace must only be set when captured")
+	}
+
+	genEvents := spans["call"].Events
+	if len(genEvents) != 1 || genEvents[0].Attributes[len(genEvents[0].Attributes)-1].Key != "exception.stacktrace" {
+		t.Errorf("generation events=%v, want one exception with stacktrace", genEvents)
+	}
+	if spans["call"].GetStatus() != nil {
+		t.Error("errors alone must not change the status")
+	}
+}
@@ -0,0 +1,246 @@
+package otel
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"sort"
+
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+)
+
+const (
+	defaultMetadataMaxKeys  = 128
+	defaultMetadataMaxDepth = 3
+)
+
+// MetadataLimits bounds the attributes a metadata value expands into.
+type MetadataLimits struct {
+	// MaxKeys caps the attributes per metadata value. Keys are taken in
+	// sorted order and the rest are dropped. Zero uses 128.
+	MaxKeys int
+	// MaxDepth caps how many levels of nested maps are flattened into dotted
+	// keys; deeper maps are stored as a JSON string. Zero uses 3.
+	MaxDepth int
+}
+
+func (l MetadataLimits) maxKeys() int {
+	if l.MaxKeys > 0 {
+		return l.MaxKeys
+	}
+	return defaultMetadataMaxKeys
+}
+
+func (l MetadataLimits) maxDepth() int {
+	if l.MaxDepth > 0 {
+		return l.MaxDepth
+	}
+	return defaultMetadataMaxDepth
+}
+
+// appendMetadataAttrs expands metadata into attributes under prefix. Maps
+// with string keys, including named map types such as model.M, and structs
+// are flattened into dotted keys; numbers, bools and arrays of scalars keep
+// their native OTLP type. Metadata that is not an object is stored as JSON
+// under prefix+"raw".
+func appendMetadataAttrs(prefix string, metadata any, attrs []*commonv1.KeyValue, limits MetadataLimits) []*commonv1.KeyValue {
+	if metadata == nil {
+		return attrs
+	}
+
+	m, ok := metadataMap(metadata)
+	if !ok {
+		return append(attrs, attrString(prefix+"raw", jsonString(metadata)))
+	}
+
+	budget := limits.maxKeys()
+	return flattenMetadata(prefix, m, 1, limits.maxDepth(), &budget, attrs)
+}
+
+func flattenMetadata(prefix string, m map[string]any, depth, maxDepth int, budget *int, attrs []*commonv1.KeyValue) []*commonv1.KeyValue {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if *budget <= 0 {
+			return attrs
+		}
+
+		value := m[key]
+		if nested, ok := metadataMap(value); ok {
+			if depth < maxDepth {
+				attrs = flattenMetadata(prefix+key+".", nested, depth+1, maxDepth, budget, attrs)
+				continue
+			}
+			value = jsonString(value)
+		}
+
+		if attr := metadataAttr(prefix+key, value); attr != nil {
+			attrs = append(attrs, attr)
+			*budget--
+		}
+	}
+
+	return attrs
+}
+
+// metadataMap returns v as a map when it is a map with string keys or a
+// struct. Other values report false.
+func metadataMap(v any) (map[string]any, bool) {
+	switch m := v.(type) {
+	case map[string]any:
+		return m, true
+	case map[string]string:
+		out := make(map[string]any, len(m))
+		for key, value := range m {
+			out[key] = value
+		}
+		return out, true
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	switch {
+	case rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String:
+		out := make(map[string]any, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			out[iter.Key().String()] = iter.Value().Interface()
+		}
+		return out, true
+	case rv.Kind() == reflect.Struct:
+		// Structs go through JSON so that json tags name the keys.
+		decoded, ok := jsonRoundTrip(v).(map[string]any)
+		return decoded, ok
+	default:
+		return nil, false
+	}
+}
+
+// metadataAttr encodes one metadata value with its native OTLP type. String
+// values are stored verbatim so exact-match metadata filters keep working.
+func metadataAttr(key string, value any) *commonv1.KeyValue {
+	if value == nil {
+		return nil
+	}
+
+	if anyValue, ok := scalarValue(value); ok {
+		return &commonv1.KeyValue{Key: key, Value: anyValue}
+	}
+
+	if array, ok := scalarArray(value); ok {
+		return &commonv1.KeyValue{Key: key, Value: array}
+	}
+
+	// Values with a custom JSON form, such as time.Time, keep that form.
+	switch decoded := jsonRoundTrip(value).(type) {
+	case string, bool, json.Number:
+		anyValue, _ := scalarValue(decoded)
+		return &commonv1.KeyValue{Key: key, Value: anyValue}
+	case []any:
+		if array, ok := scalarArray(decoded); ok {
+			return &commonv1.KeyValue{Key: key, Value: array}
+		}
+	}
+
+	return attrString(key, jsonString(value))
+}
+
+// scalarValue maps strings, bools and numbers to an AnyValue.
+func scalarValue(value any) (*commonv1.AnyValue, bool) {
+	switch v := value.(type) {
+	case string:
+		return &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: v}}, true
+	case bool:
+		return &commonv1.AnyValue{Value: &commonv1.AnyValue_BoolValue{BoolValue: v}}, true
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return intValue(i), true
+		}
+		if f, err := v.Float64(); err == nil {
+			return doubleValue(f), true
+		}
+		return &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: v.String()}}, true
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return intValue(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := rv.Uint(); u <= math.MaxInt64 {
+			return intValue(int64(u)), true
+		}
+		return doubleValue(float64(rv.Uint())), true
+	case reflect.Float32, reflect.Float64:
+		return doubleValue(rv.Float()), true
+	case reflect.String:
+		return &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: rv.String()}}, true
+	case reflect.Bool:
+		return &commonv1.AnyValue{Value: &commonv1.AnyValue_BoolValue{BoolValue: rv.Bool()}}, true
+	default:
+		return nil, false
+	}
+}
+
+// scalarArray maps slices and arrays whose elements are all scalars to an
+// OTLP array value.
+func scalarArray(value any) (*commonv1.AnyValue, bool) {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+		// []byte is binary data, not a list of numbers.
+		return nil, false
+	}
+
+	values := make([]*commonv1.AnyValue, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		element := rv.Index(i).Interface()
+		anyValue, ok := scalarValue(element)
+		if !ok {
+			return nil, false
+		}
+		values = append(values, anyValue)
+	}
+
+	return &commonv1.AnyValue{Value: &commonv1.AnyValue_ArrayValue{
+		ArrayValue: &commonv1.ArrayValue{Values: values},
+	}}, true
+}
+
+func intValue(v int64) *commonv1.AnyValue {
+	return &commonv1.AnyValue{Value: &commonv1.AnyValue_IntValue{IntValue: v}}
+}
+
+func doubleValue(v float64) *commonv1.AnyValue {
+	return &commonv1.AnyValue{Value: &commonv1.AnyValue_DoubleValue{DoubleValue: v}}
+}
+
+// jsonRoundTrip encodes v as JSON and decodes it back into plain maps,
+// slices and json.Number values. It returns nil when v cannot be encoded.
+func jsonRoundTrip(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var out any
+	if err := decoder.Decode(&out); err != nil {
+		return nil
+	}
+	return out
+}
@@ -0,0 +1,83 @@
+package otel
+
+import (
+	"runtime/debug"
+	"sort"
+
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcev1 "go.opentelemetry.io/proto/otlp/resource/v1"
+)
+
+const modulePath = "github.com/ezardev-team/langfuse-go"
+
+// Resource describes the service emitting spans.
+type Resource struct {
+	// ServiceName defaults to "langfuse-go".
+	ServiceName           string
+	ServiceVersion        string
+	DeploymentEnvironment string
+	// Attributes are added after the fields above and override them on
+	// collision.
+	Attributes map[string]any
+}
+
+func (r Resource) proto() *resourcev1.Resource {
+	serviceName := r.ServiceName
+	if serviceName == "" {
+		serviceName = instrumentationName
+	}
+
+	attrs := map[string]*commonv1.KeyValue{
+		"service.name":           attrString("service.name", serviceName),
+		"telemetry.sdk.name":     attrString("telemetry.sdk.name", instrumentationName),
+		"telemetry.sdk.language": attrString("telemetry.sdk.language", "go"),
+		"telemetry.sdk.version":  attrString("telemetry.sdk.version", sdkVersion),
+	}
+	if r.ServiceVersion != "" {
+		attrs["service.version"] = attrString("service.version", r.ServiceVersion)
+	}
+	if r.DeploymentEnvironment != "" {
+		attrs["deployment.environment.name"] = attrString("deployment.environment.name", r.DeploymentEnvironment)
+	}
+	for key, value := range r.Attributes {
+		if attr := attrAny(key, value); attr != nil {
+			attrs[key] = attr
+		}
+	}
+
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	resource := &resourcev1.Resource{Attributes: make([]*commonv1.KeyValue, 0, len(keys))}
+	for _, key := range keys {
+		resource.Attributes = append(resource.Attributes, attrs[key])
+	}
+
+	return resource
+}
+
+// sdkVersion is the version of this module as recorded in the build info, or
+// "devel" when it is not available (e.g. in tests).
+var sdkVersion = readSDKVersion()
+
+func readSDKVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "devel"
+	}
+	if info.Main.Path == modulePath && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			if dep.Replace != nil && dep.Replace.Version != "" {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "devel"
+}
@@ -0,0 +1,258 @@
+package otel
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"unicode/utf8"
+
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+)
+
+const (
+	defaultMaxFieldBytes = 1 << 20
+	defaultMaxSpanBytes  = 4 << 20
+
+	// truncationMarker ends every string shortened to fit a size limit.
+	truncationMarker = "…[truncated]"
+	// originalBytesPrefix prefixes the attribute recording the size of a
+	// truncated payload, e.g. "langfuse_go.original_bytes.langfuse.observation.input".
+	originalBytesPrefix = "langfuse_go.original_bytes."
+	// minTruncatedString is the shortest string prefix kept when shrinking.
+	minTruncatedString = 16
+	// minSizeLimit is the smallest limit a payload is truncated to: the size
+	// of truncationMarker as a JSON string.
+	minSizeLimit = len(truncationMarker) + 2
+)
+
+// payloadKeys are the attributes holding JSON payloads that may be truncated.
+var payloadKeys = map[string]bool{
+	"langfuse.trace.input":        true,
+	"langfuse.trace.output":       true,
+	"langfuse.observation.input":  true,
+	"langfuse.observation.output": true,
+}
+
+// SizeLimits bounds the size of exported payloads in bytes.
+type SizeLimits struct {
+	// MaxFieldBytes caps each input and output. Zero uses 1 MiB; a negative
+	// value disables the limit. Positive values below 16, the size of the
+	// truncation marker, are raised to 16.
+	MaxFieldBytes int
+	// MaxSpanBytes caps the attribute values of one span; inputs and outputs
+	// are shortened further to fit, but not below 16 bytes. Zero uses 4 MiB;
+	// a negative value disables the limit.
+	MaxSpanBytes int
+}
+
+func (l SizeLimits) maxFieldBytes() int {
+	switch {
+	case l.MaxFieldBytes == 0:
+		return defaultMaxFieldBytes
+	case l.MaxFieldBytes < 0:
+		return l.MaxFieldBytes
+	}
+	return max(l.MaxFieldBytes, minSizeLimit)
+}
+
+func (l SizeLimits) maxSpanBytes() int {
+	if l.MaxSpanBytes == 0 {
+		return defaultMaxSpanBytes
+	}
+	return l.MaxSpanBytes
+}
+
+// payloadAttrs encodes value as the JSON payload attribute key, truncated to
+// the field limit. A truncated payload is followed by its original size.
+func (e Encoder) payloadAttrs(key string, value any) []*commonv1.KeyValue {
+	encoded := jsonString(value)
+	limit := e.SizeLimits.maxFieldBytes()
+	if limit < 0 || len(encoded) <= limit {
+		return []*commonv1.KeyValue{attrString(key, encoded)}
+	}
+
+	e.truncated()
+	return []*commonv1.KeyValue{
+		attrString(key, truncateJSON(encoded, limit)),
+		attrInt(originalBytesPrefix+key, int64(len(encoded))),
+	}
+}
+
+// limitSpanSize shortens the payload attributes of a span, largest first,
+// until the span attribute values fit the span limit. Shortened attributes are
+// replaced, not modified, as attributes may be shared between spans. A payload
+// already cut to the field limit is not counted as truncated again.
+func (e Encoder) limitSpanSize(attrs []*commonv1.KeyValue) []*commonv1.KeyValue {
+	limit := e.SizeLimits.maxSpanBytes()
+	if limit < 0 {
+		return attrs
+	}
+
+	total := 0
+	var payloads []int
+	for i, kv := range attrs {
+		if kv == nil {
+			continue
+		}
+		total += len(kv.Key) + attrSize(kv.Value)
+		if payloadKeys[kv.Key] {
+			payloads = append(payloads, i)
+		}
+	}
+	if total <= limit {
+		return attrs
+	}
+
+	sort.SliceStable(payloads, func(i, j int) bool {
+		return len(attrs[payloads[i]].Value.GetStringValue()) > len(attrs[payloads[j]].Value.GetStringValue())
+	})
+
+	for _, i := range payloads {
+		excess := total - limit
+		if excess <= 0 {
+			break
+		}
+
+		key := attrs[i].Key
+		current := attrs[i].Value.GetStringValue()
+		shortened := truncateJSON(current, max(len(current)-excess, minSizeLimit))
+		if len(shortened) >= len(current) {
+			continue
+		}
+
+		attrs[i] = attrString(key, shortened)
+		total -= len(current) - len(shortened)
+		if !hasAttr(attrs, originalBytesPrefix+key) {
+			e.truncated()
+			attrs = append(attrs, attrInt(originalBytesPrefix+key, int64(len(current))))
+		}
+	}
+
+	return attrs
+}
+
+func (e Encoder) truncated() {
+	if e.OnTruncate != nil {
+		e.OnTruncate()
+	}
+}
+
+func hasAttr(attrs []*commonv1.KeyValue, key string) bool {
+	for _, kv := range attrs {
+		if kv != nil && kv.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// attrSize approximates the encoded size of an attribute value.
+func attrSize(v *commonv1.AnyValue) int {
+	switch value := v.GetValue().(type) {
+	case *commonv1.AnyValue_StringValue:
+		return len(value.StringValue)
+	case *commonv1.AnyValue_ArrayValue:
+		size := 0
+		for _, element := range value.ArrayValue.GetValues() {
+			size += attrSize(element)
+		}
+		return size
+	default:
+		return 8
+	}
+}
+
+// truncateJSON shortens the JSON document encoded to at most limit bytes and
+// keeps it valid JSON. Long strings and arrays are cut first so the document
+// keeps its shape; when that is not enough the document is replaced by a
+// JSON string holding a prefix of its text. Cut strings end with
+// truncationMarker and cut arrays end with a truncationMarker element. Below
+// minSizeLimit the marker does not fit and the document becomes an empty
+// JSON string, or nothing at all when limit is under 2.
+func truncateJSON(encoded string, limit int) string {
+	if len(encoded) <= limit {
+		return encoded
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(encoded)))
+	decoder.UseNumber()
+	var tree any
+	if err := decoder.Decode(&tree); err == nil {
+		maxItems := 64
+		for maxString := limit / 2; maxString >= minTruncatedString; maxString /= 2 {
+			shrunk, err := marshalNoEscape(shrinkJSON(tree, maxString, maxItems))
+			if err == nil && len(shrunk) <= limit {
+				return shrunk
+			}
+			maxItems = max(maxItems/2, 1)
+		}
+	}
+
+	// Fall back to a JSON string holding a prefix of the document.
+	for budget := limit - len(truncationMarker) - 2; budget > 0; budget -= max(budget/8, 1) {
+		s, err := marshalNoEscape(truncateString(encoded, budget))
+		if err == nil && len(s) <= limit {
+			return s
+		}
+	}
+
+	if s, _ := marshalNoEscape(truncationMarker); len(s) <= limit {
+		return s
+	}
+	if limit >= 2 {
+		return `""`
+	}
+	return ""
+}
+
+// shrinkJSON returns a copy of tree with strings cut to maxString bytes and
+// arrays cut to maxItems elements.
+func shrinkJSON(tree any, maxString, maxItems int) any {
+	switch v := tree.(type) {
+	case string:
+		if len(v) > maxString {
+			return truncateString(v, maxString)
+		}
+		return v
+	case []any:
+		n := min(len(v), maxItems)
+		out := make([]any, 0, n+1)
+		for _, element := range v[:n] {
+			out = append(out, shrinkJSON(element, maxString, maxItems))
+		}
+		if len(v) > n {
+			out = append(out, truncationMarker)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, value := range v {
+			out[key] = shrinkJSON(value, maxString, maxItems)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// truncateString cuts s to at most n bytes on a rune boundary and appends
+// truncationMarker.
+func truncateString(s string, n int) string {
+	if n >= len(s) {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + truncationMarker
+}
+
+func marshalNoEscape(v any) (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return "", err
+	}
+	return string(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))), nil
+}
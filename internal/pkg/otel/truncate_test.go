@@ -0,0 +1,143 @@
+package otel
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+func TestTruncateJSON(t *testing.T) {
+	long := strings.Repeat("é", 2000)
+	items := make([]int, 1000)
+
+	for name, value := range map[string]any{
+		"string": long,
+		"object": map[string]any{"context": long, "question": "why?"},
+		"array":  items,
+		"nested": []any{map[string]any{"docs": []string{long, long, long}}},
+	} {
+		encoded := jsonString(value)
+		for _, limit := range []int{40, 200, 1000} {
+			got := truncateJSON(encoded, limit)
+			if len(got) > limit {
+				t.Errorf("%s/%d: len=%d, over the limit", name, limit, len(got))
+			}
+			if !json.Valid([]byte(got)) {
+				t.Errorf("%s/%d: invalid JSON %s", name, limit, got)
+			}
+			if !strings.Contains(got, truncationMarker) {
+				t.Errorf("%s/%d: no truncation marker in %s", name, limit, got)
+			}
+		}
+	}
+
+	// Shape is kept when shrinking strings is enough.
+	got := truncateJSON(jsonString(map[string]any{"context": long, "question": "why?"}), 500)
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(got), &decoded); err != nil || decoded["question"] != "why?" {
+		t.Errorf("object shape lost: %s", got)
+	}
+
+	if got := truncateJSON(`{"a":1}`, 100); got != `{"a":1}` {
+		t.Errorf("small payload changed: %s", got)
+	}
+
+	for _, limit := range []int{0, 1, 2, 10, minSizeLimit} {
+		if got := truncateJSON(jsonString(long), limit); len(got) > limit {
+			t.Errorf("limit %d: %q is over the limit", limit, got)
+		}
+	}
+}
+
+func TestEncoder_SizeLimits(t *testing.T) {
+	now := time.Now()
+	big := strings.Repeat("x", 5000)
+	truncations := 0
+	encoder := Encoder{
+		SizeLimits: SizeLimits{MaxFieldBytes: 1000, MaxSpanBytes: 1500},
+		OnTruncate: func() { truncations++ },
+	}
+
+	data, err := encoder.Encode([]model.IngestionEvent{
+		{Type: model.IngestionEventTypeSpanCreate, Timestamp: now, Body: &model.Span{
+			ID: "a", TraceID: "t", Name: "field-limit", Input: big, Output: "small",
+		}},
+		{Type: model.IngestionEventTypeSpanCreate, Timestamp: now, Body: &model.Span{
+			ID: "b", TraceID: "t", Name: "span-limit", Input: strings.Repeat("i", 900), Output: strings.Repeat("o", 900),
+		}},
+		{Type: model.IngestionEventTypeSpanCreate, Timestamp: now, Body: &model.Span{
+			ID: "c", TraceID: "t", Name: "fits", Input: "hello",
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	spans := decodeSpans(t, data)
+
+	attrs := spans["field-limit"].Attributes
+	input, _ := attrValue(attrs, "langfuse.observation.input")
+	if len(input) > 1000 || !json.Valid([]byte(input)) || !strings.HasSuffix(input, truncationMarker+`"`) {
+		t.Errorf("input len=%d, want valid truncated JSON", len(input))
+	}
+	if got := attrAnyValue(attrs, originalBytesPrefix+"langfuse.observation.input"); got.GetIntValue() != int64(len(big)+2) {
+		t.Errorf("original bytes=%v, want %d", got, len(big)+2)
+	}
+	if output, _ := attrValue(attrs, "langfuse.observation.output"); output != `"small"` {
+		t.Errorf("output=%s, want unchanged", output)
+	}
+
+	size := 0
+	for _, kv := range spans["span-limit"].Attributes {
+		size += len(kv.Key) + attrSize(kv.Value)
+	}
+	if size > 1500+len(originalBytesPrefix)+len("langfuse.observation.output")+8 {
+		t.Errorf("span-limit attributes=%d bytes, want about 1500", size)
+	}
+
+	if attrAnyValue(spans["fits"].Attributes, originalBytesPrefix+"langfuse.observation.input") != nil {
+		t.Error("payloads under the limits must not be marked")
+	}
+	if truncations < 2 {
+		t.Errorf("truncations=%d, want at least 2", truncations)
+	}
+}
+
+func TestEncoder_SizeLimitsCountsFieldOnce(t *testing.T) {
+	truncations := 0
+	encoder := Encoder{
+		SizeLimits: SizeLimits{MaxFieldBytes: 1000, MaxSpanBytes: 600},
+		OnTruncate: func() { truncations++ },
+	}
+
+	data, err := encoder.Encode([]model.IngestionEvent{
+		{Type: model.IngestionEventTypeSpanCreate, Timestamp: time.Now(), Body: &model.Span{
+			ID: "a", TraceID: "t", Name: "both-limits", Input: strings.Repeat("x", 5000),
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if input, _ := attrValue(decodeSpans(t, data)["both-limits"].Attributes, "langfuse.observation.input"); len(input) > 600 {
+		t.Errorf("input len=%d, want it cut to the span limit", len(input))
+	}
+	if truncations != 1 {
+		t.Errorf("truncations=%d, want 1", truncations)
+	}
+}
+
+func TestEncoder_SizeLimitsDisabled(t *testing.T) {
+	big := strings.Repeat("x", 2<<20)
+	data, err := Encoder{SizeLimits: SizeLimits{MaxFieldBytes: -1, MaxSpanBytes: -1}}.Encode([]model.IngestionEvent{
+		{Type: model.IngestionEventTypeSpanCreate, Timestamp: time.Now(), Body: &model.Span{ID: "a", TraceID: "t", Name: "big", Input: big}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if input, _ := attrValue(decodeSpans(t, data)["big"].Attributes, "langfuse.observation.input"); len(input) != len(big)+2 {
+		t.Errorf("input len=%d, want untouched", len(input))
+	}
+}
@@ -2,8 +2,10 @@ package langfuse
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/ezardev-team/langfuse-go/internal/pkg/api"
@@ -14,31 +16,51 @@ import (
 )
 
 const (
-	defaultFlushInterval = 500 * time.Millisecond
+	defaultFlushInterval         = 500 * time.Millisecond
+	defaultExperimentConcurrency = 4
 )
 
 type Langfuse struct {
-	flushInterval time.Duration
-	client        *api.Client
-	observer      *observer.Observer[model.IngestionEvent]
+	ctx                   context.Context
+	flushInterval         time.Duration
+	experimentConcurrency int
+	client                *api.Client
+	observer              *observer.Observer[model.IngestionEvent]
+	scoreConfigs          sync.Map // config ID -> *model.ScoreConfig
+	encoder               otel.Encoder
+	otlpEncoding          OTLPEncoding
+	gzip                  bool
+	mask                  MaskFunc
+	mediaUploads          bool
+	mediaIDs              recentMap[string, string] // trace, observation, field and hash -> media ID
+	traceEnvironments     recentMap[string, string] // trace ID -> environment
+	sampler               Sampler
+	samplingDecisions     samplingDecisions
+	processors            []EventProcessor
+	droppedTraces         recentMap[string, struct{}] // IDs of traces dropped by a processor
+	exportErrorHandler    func(error)
+	exportStats           exportStats
 }
 
 func New(ctx context.Context) *Langfuse {
 	client := api.New()
 
 	l := &Langfuse{
-		flushInterval: defaultFlushInterval,
-		client:        client,
-		observer: observer.NewObserver(
-			ctx,
-			func(ctx context.Context, events []model.IngestionEvent) {
-				err := ingest(ctx, client, events)
-				if err != nil {
-					fmt.Println(err)
-				}
-			},
-		),
+		ctx:                   ctx,
+		flushInterval:         defaultFlushInterval,
+		experimentConcurrency: defaultExperimentConcurrency,
+		client:                client,
+		encoder:               otel.Encoder{Environment: environmentFromEnv()},
 	}
+	l.observer = observer.NewObserver(
+		ctx,
+		func(ctx context.Context, events []model.IngestionEvent) {
+			err := l.ingest(ctx, events)
+			if err != nil {
+				l.handleExportError(err)
+			}
+		},
+	)
 
 	return l
 }
@@ -48,22 +70,93 @@ func (l *Langfuse) WithFlushInterval(d time.Duration) *Langfuse {
 	return l
 }
 
-func ingest(ctx context.Context, client *api.Client, events []model.IngestionEvent) error {
-	payload, err := otel.EncodeEvents(events)
+// WithExperimentConcurrency sets how many dataset items RunExperiment
+// processes at once. Values below 1 are treated as 1.
+func (l *Langfuse) WithExperimentConcurrency(n int) *Langfuse {
+	l.experimentConcurrency = n
+	return l
+}
+
+// ingest exports a batch of events. Scores have no OTLP representation, so
+// score-create events are posted to the scores API one by one; everything
+// else has its media uploaded, is masked and is sent as a single OTLP
+// request.
+func (l *Langfuse) ingest(ctx context.Context, events []model.IngestionEvent) error {
+	traceEvents := make([]model.IngestionEvent, 0, len(events))
+	var scoreEvents []model.IngestionEvent
+	for _, event := range events {
+		if event.Type == model.IngestionEventTypeScoreCreate {
+			scoreEvents = append(scoreEvents, event)
+			continue
+		}
+		traceEvents = append(traceEvents, event)
+	}
+
+	var errs []error
+	if len(traceEvents) > 0 {
+		err := l.exportTraces(ctx, l.uploadMediaEvents(ctx, l.maskEvents(traceEvents)))
+		l.exportStats.update(func(s *ExportStats) {
+			s.Batches++
+			s.Events += uint64(len(traceEvents))
+			var partial *PartialExportError
+			switch {
+			case errors.As(err, &partial):
+				s.RejectedSpans += uint64(max(partial.RejectedSpans, 0))
+			case err != nil:
+				s.FailedBatches++
+			}
+		})
+		errs = append(errs, err)
+	}
+
+	for _, event := range l.maskEvents(scoreEvents) {
+		score, ok := event.Body.(*model.Score)
+		if !ok {
+			continue
+		}
+		err := createScore(ctx, l.client, score)
+		l.exportStats.update(func(s *ExportStats) {
+			s.Scores++
+			if err != nil {
+				s.FailedScores++
+			}
+		})
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// exportTraces sends events as one OTLP request. A partial success reported
+// by the endpoint is returned as a *PartialExportError.
+func (l *Langfuse) exportTraces(ctx context.Context, events []model.IngestionEvent) error {
+	req, err := l.exportRequest(events)
 	if err != nil {
 		return err
 	}
+	res := api.OpenTelemetryResponse{}
 
-	req := api.OpenTelemetryTracesRequest{
-		Body: payload,
+	if err := l.client.OpenTelemetryTraces(ctx, req, &res); err != nil {
+		return err
 	}
-	res := api.OpenTelemetryResponse{}
-	return client.OpenTelemetryTraces(ctx, &req, &res)
+
+	if !res.IsSuccess() {
+		return newAPIError("ingestion", req, res.Code, res.RawBody)
+	}
+
+	if res.RejectedSpans > 0 || res.ErrorMessage != "" {
+		return &PartialExportError{RejectedSpans: res.RejectedSpans, Message: res.ErrorMessage}
+	}
+
+	return nil
 }
 
 func (l *Langfuse) Trace(t *model.Trace) (*model.Trace, error) {
+	if err := validateOptionalEnvironment(t.Environment); err != nil {
+		return nil, err
+	}
 	t.ID = buildID(&t.ID)
-	l.observer.Dispatch(
+	l.dispatch(
 		model.IngestionEvent{
 			ID:        buildID(nil),
 			Type:      model.IngestionEventTypeTraceCreate,
@@ -75,6 +168,9 @@ func (l *Langfuse) Trace(t *model.Trace) (*model.Trace, error) {
 }
 
 func (l *Langfuse) Generation(g *model.Generation, parentID *string) (*model.Generation, error) {
+	if err := validateOptionalEnvironment(g.Environment); err != nil {
+		return nil, err
+	}
 	if g.TraceID == "" {
 		traceID, err := l.createTrace(g.Name)
 		if err != nil {
@@ -90,7 +186,7 @@ func (l *Langfuse) Generation(g *model.Generation, parentID *string) (*model.Gen
 		g.ParentObservationID = *parentID
 	}
 
-	l.observer.Dispatch(
+	l.dispatch(
 		model.IngestionEvent{
 			ID:        buildID(nil),
 			Type:      model.IngestionEventTypeGenerationCreate,
@@ -110,7 +206,7 @@ func (l *Langfuse) GenerationEnd(g *model.Generation) (*model.Generation, error)
 		return nil, fmt.Errorf("trace ID is required")
 	}
 
-	l.observer.Dispatch(
+	l.dispatch(
 		model.IngestionEvent{
 			ID:        buildID(nil),
 			Type:      model.IngestionEventTypeGenerationUpdate,
@@ -126,9 +222,12 @@ func (l *Langfuse) Score(s *model.Score) (*model.Score, error) {
 	if s.TraceID == "" {
 		return nil, fmt.Errorf("trace ID is required")
 	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
 	s.ID = buildID(&s.ID)
 
-	l.observer.Dispatch(
+	l.dispatch(
 		model.IngestionEvent{
 			ID:        buildID(nil),
 			Type:      model.IngestionEventTypeScoreCreate,
@@ -140,6 +239,9 @@ func (l *Langfuse) Score(s *model.Score) (*model.Score, error) {
 }
 
 func (l *Langfuse) Span(s *model.Span, parentID *string) (*model.Span, error) {
+	if err := validateOptionalEnvironment(s.Environment); err != nil {
+		return nil, err
+	}
 	if s.TraceID == "" {
 		traceID, err := l.createTrace(s.Name)
 		if err != nil {
@@ -155,7 +257,7 @@ func (l *Langfuse) Span(s *model.Span, parentID *string) (*model.Span, error) {
 		s.ParentObservationID = *parentID
 	}
 
-	l.observer.Dispatch(
+	l.dispatch(
 		model.IngestionEvent{
 			ID:        buildID(nil),
 			Type:      model.IngestionEventTypeSpanCreate,
@@ -176,7 +278,7 @@ func (l *Langfuse) SpanEnd(s *model.Span) (*model.Span, error) {
 		return nil, fmt.Errorf("trace ID is required")
 	}
 
-	l.observer.Dispatch(
+	l.dispatch(
 		model.IngestionEvent{
 			ID:        buildID(nil),
 			Type:      model.IngestionEventTypeSpanUpdate,
@@ -189,6 +291,9 @@ func (l *Langfuse) SpanEnd(s *model.Span) (*model.Span, error) {
 }
 
 func (l *Langfuse) Event(e *model.Event, parentID *string) (*model.Event, error) {
+	if err := validateOptionalEnvironment(e.Environment); err != nil {
+		return nil, err
+	}
 	if e.TraceID == "" {
 		traceID, err := l.createTrace(e.Name)
 		if err != nil {
@@ -204,7 +309,7 @@ func (l *Langfuse) Event(e *model.Event, parentID *string) (*model.Event, error)
 		e.ParentObservationID = *parentID
 	}
 
-	l.observer.Dispatch(
+	l.dispatch(
 		model.IngestionEvent{
 			ID:        uuid.New().String(),
 			Type:      model.IngestionEventTypeEventCreate,
@@ -239,12 +344,9 @@ func (l *Langfuse) Prompt(ctx context.Context, name string, options *model.Promp
 	}
 
 	if !res.IsSuccess() {
-		if res.RawBody != nil {
-			log.Printf("prompt request failed with status code: %d (path=%s) body=%s", res.Code, path, *res.RawBody)
-			return nil, fmt.Errorf("prompt request failed: %s", *res.RawBody)
-		}
-		log.Printf("prompt request failed with status code: %d (path=%s)", res.Code, path)
-		return nil, fmt.Errorf("prompt request failed with status code: %d", res.Code)
+		apiErr := newAPIError("prompt", &req, res.Code, res.RawBody)
+		log.Print(apiErr)
+		return nil, apiErr
 	}
 
 	return &res.Prompt, nil
@@ -260,7 +362,7 @@ func (l *Langfuse) createTrace(traceName string) (string, error) {
 		return "", errTrace
 	}
 
-	return trace.ID, fmt.Errorf("unable to get trace ID")
+	return trace.ID, nil
 }
 
 func (l *Langfuse) Flush(ctx context.Context) {
@@ -594,3 +594,32 @@ func TestTracing_OtelEncodingAndSend(t *testing.T) {
 			obs.Type, obs.Name, obs.Model, obs.UsageDetails["total"])
 	}
 }
+
+// TestObservationWithoutTraceID verifies that observations created without a
+// TraceID get a trace named after them instead of failing.
+func TestObservationWithoutTraceID(t *testing.T) {
+	var (
+		generation *model.Generation
+		traces     []model.Trace
+		err        error
+	)
+	captureOTLP(t, func(l *Langfuse) {
+		l.WithEventProcessor(EventProcessorFunc(func(_ context.Context, event *model.IngestionEvent) bool {
+			if trace, ok := event.Body.(*model.Trace); ok {
+				traces = append(traces, *trace)
+			}
+			return true
+		}))
+		generation, err = l.Generation(&model.Generation{Name: "standalone"}, nil)
+	})
+	if err != nil {
+		t.Fatalf("Generation: %v", err)
+	}
+	if generation.TraceID == "" {
+		t.Fatal("TraceID was not set")
+	}
+
+	if len(traces) != 1 || traces[0].ID != generation.TraceID || traces[0].Name != "standalone" {
+		t.Errorf("traces=%+v, want one trace %s named after the observation", traces, generation.TraceID)
+	}
+}
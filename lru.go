@@ -0,0 +1,87 @@
+package langfuse
+
+import (
+	"container/list"
+	"sync"
+)
+
+// defaultRecentEntries bounds a recentMap whose max is not set.
+const defaultRecentEntries = 1 << 16
+
+// recentMap is a size-bounded map that forgets its least recently used key
+// first. Lookups and stores both refresh a key. The zero value is ready to
+// use.
+type recentMap[K comparable, V any] struct {
+	mu      sync.Mutex
+	max     int
+	order   *list.List // most recently used at the front
+	entries map[K]*list.Element
+}
+
+type recentEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// get returns the value of key and marks it as recently used.
+func (m *recentMap[K, V]) get(key K) (V, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.getLocked(key)
+}
+
+// set stores value under key and marks it as recently used.
+func (m *recentMap[K, V]) set(key K, value V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.setLocked(key, value)
+}
+
+// getOrSet returns the value of key, storing the result of newValue first
+// if there is none. newValue runs with the map locked.
+func (m *recentMap[K, V]) getOrSet(key K, newValue func() V) V {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if value, ok := m.getLocked(key); ok {
+		return value
+	}
+	value := newValue()
+	m.setLocked(key, value)
+	return value
+}
+
+func (m *recentMap[K, V]) getLocked(key K) (V, bool) {
+	e, ok := m.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	m.order.MoveToFront(e)
+	return e.Value.(*recentEntry[K, V]).value, true
+}
+
+func (m *recentMap[K, V]) setLocked(key K, value V) {
+	if e, ok := m.entries[key]; ok {
+		e.Value.(*recentEntry[K, V]).value = value
+		m.order.MoveToFront(e)
+		return
+	}
+	if m.entries == nil {
+		m.entries = make(map[K]*list.Element)
+		m.order = list.New()
+	}
+	m.entries[key] = m.order.PushFront(&recentEntry[K, V]{key: key, value: value})
+
+	max := m.max
+	if max <= 0 {
+		max = defaultRecentEntries
+	}
+	for m.order.Len() > max {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*recentEntry[K, V]).key)
+	}
+}
@@ -0,0 +1,29 @@
+package langfuse
+
+import "testing"
+
+func TestRecentMap_EvictsLeastRecentlyUsed(t *testing.T) {
+	m := recentMap[string, int]{max: 2}
+	m.set("a", 1)
+	m.set("b", 2)
+	if _, ok := m.get("a"); !ok {
+		t.Fatal("a missing")
+	}
+	m.set("c", 3)
+
+	if _, ok := m.get("b"); ok {
+		t.Error("b should have been evicted")
+	}
+	if v, ok := m.get("a"); !ok || v != 1 {
+		t.Errorf("a=%d,%v, want 1 after being looked up", v, ok)
+	}
+	if got := m.getOrSet("c", func() int { t.Error("c is stored"); return 0 }); got != 3 {
+		t.Errorf("c=%d, want 3", got)
+	}
+	if got := m.getOrSet("d", func() int { return 4 }); got != 4 {
+		t.Errorf("d=%d, want 4", got)
+	}
+	if len(m.entries) != 2 || m.order.Len() != 2 {
+		t.Errorf("entries=%d, order=%d, want 2", len(m.entries), m.order.Len())
+	}
+}
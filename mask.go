@@ -0,0 +1,455 @@
+package langfuse
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+// MaskField names the payload a MaskFunc is applied to.
+type MaskField string
+
+const (
+	MaskFieldInput    MaskField = "input"
+	MaskFieldOutput   MaskField = "output"
+	MaskFieldMetadata MaskField = "metadata"
+	// MaskFieldStatusMessage is the status message of an observation.
+	MaskFieldStatusMessage MaskField = "status_message"
+	// MaskFieldError is the message of an error recorded on an observation.
+	MaskFieldError MaskField = "error"
+	// MaskFieldComment is the comment of a score.
+	MaskFieldComment MaskField = "comment"
+)
+
+// Placeholders written in place of masked values.
+const (
+	// MaskRedacted replaces values masked with MaskReplace.
+	MaskRedacted = "[REDACTED]"
+	// MaskFailed replaces a whole payload when masking it failed.
+	MaskFailed = "[MASKING FAILED]"
+)
+
+// MaskFunc returns the masked form of a trace or observation payload. It
+// must not modify value in place. Status and error messages are passed as
+// strings; a result that is not a string is sent as its JSON form. When the
+// mask returns an error or panics, the payload is replaced with MaskFailed
+// and the error is passed to the export error handler; the original value is
+// never sent.
+type MaskFunc func(field MaskField, value any) (any, error)
+
+// WithMask sets fn to mask the input, output and metadata of every trace and
+// observation, the status and error messages of observations and the
+// comments of scores before they are exported. NewMask builds a MaskFunc from rules.
+func (l *Langfuse) WithMask(fn MaskFunc) *Langfuse {
+	l.mask = fn
+	return l
+}
+
+// MaskAction is what a MaskRule does with a matched value.
+type MaskAction int
+
+const (
+	// MaskReplace replaces the value with MaskRedacted.
+	MaskReplace MaskAction = iota
+	// MaskHash replaces the value with "hmac:" and the first 16 hex digits
+	// of its HMAC-SHA256 under the mask key, so equal values can still be
+	// correlated but short values such as phone numbers cannot be recovered
+	// by hashing every candidate.
+	MaskHash
+	// MaskDrop removes the object key, or the matched text within a string.
+	MaskDrop
+)
+
+// MaskRule matches sensitive values. Pattern matches text inside string
+// values; Keys matches object keys (case-insensitively) at any depth; Paths
+// matches dotted paths from the payload root, where "*" stands for any key or
+// array index, e.g. "messages.*.content".
+type MaskRule struct {
+	Pattern *regexp.Regexp
+	// Validate optionally filters Pattern matches, e.g. with a checksum.
+	Validate func(match string) bool
+	Keys     []string
+	Paths    []string
+	Action   MaskAction
+}
+
+var (
+	emailPattern       = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	phonePattern       = regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?(?:\(\d{1,4}\)|\d{1,4})[\s.-]?\d{3,4}[\s.-]?\d{3,4}|\(\d{2,4}\)[\s.-]?\d{3,4}[\s.-]?\d{3,4}|\b\d{2,4}[\s.]\d{3,4}[\s.-]\d{3,4}|\b\d{3}-\d{3,4}-\d{4})\b`)
+	creditCardPattern  = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+	bearerTokenPattern = regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`)
+)
+
+// MaskEmails matches email addresses.
+func MaskEmails(action MaskAction) MaskRule {
+	return MaskRule{Pattern: emailPattern, Action: action}
+}
+
+// MaskPhoneNumbers matches phone numbers with at least eight digits that are
+// written like one: with a "+" country code, a parenthesised area code, or
+// separators between the digit groups. Runs of bare digits, such as compact
+// dates or the tail of a UUID, are left alone.
+func MaskPhoneNumbers(action MaskAction) MaskRule {
+	return MaskRule{Pattern: phonePattern, Action: action}
+}
+
+// MaskCreditCards matches 13 to 19 digit card numbers that pass the Luhn
+// check, with optional space or dash separators.
+func MaskCreditCards(action MaskAction) MaskRule {
+	return MaskRule{Pattern: creditCardPattern, Validate: luhnValid, Action: action}
+}
+
+// MaskBearerTokens matches "Bearer <token>" credentials.
+func MaskBearerTokens(action MaskAction) MaskRule {
+	return MaskRule{Pattern: bearerTokenPattern, Action: action}
+}
+
+// MaskKeys matches the values of the given object keys at any depth.
+func MaskKeys(action MaskAction, keys ...string) MaskRule {
+	return MaskRule{Keys: keys, Action: action}
+}
+
+// MaskPaths matches the values at the given dotted paths.
+func MaskPaths(action MaskAction, paths ...string) MaskRule {
+	return MaskRule{Paths: paths, Action: action}
+}
+
+// NewMask returns a MaskFunc applying rules in order. Payloads are first
+// converted to their JSON form, so structs are masked by their json field
+// names. hashKey keys the HMAC written by MaskHash rules: keep it secret, and
+// share it between processes whose hashes should match. A nil hashKey draws
+// a random key, so hashes only match within the returned mask.
+//
+//	l.WithMask(langfuse.NewMask(hashKey,
+//		langfuse.MaskCreditCards(langfuse.MaskReplace),
+//		langfuse.MaskEmails(langfuse.MaskHash),
+//		langfuse.MaskKeys(langfuse.MaskDrop, "api_key", "password"),
+//	))
+func NewMask(hashKey []byte, rules ...MaskRule) MaskFunc {
+	if hashKey == nil {
+		hashKey = make([]byte, sha256.Size)
+		_, _ = rand.Read(hashKey)
+	}
+	m := masker{hashKey: hashKey, rules: rules}
+
+	return func(_ MaskField, value any) (any, error) {
+		normalized, err := normalizeMaskValue(value)
+		if err != nil {
+			return nil, err
+		}
+
+		masked, _ := m.maskValue(normalized, nil)
+		return masked, nil
+	}
+}
+
+// masker applies rules, hashing with hashKey.
+type masker struct {
+	hashKey []byte
+	rules   []MaskRule
+}
+
+// normalizeMaskValue converts value to plain maps, slices and scalars so it
+// can be rebuilt without touching the caller's data.
+func normalizeMaskValue(value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("encode payload: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var out any
+	if err := decoder.Decode(&out); err != nil {
+		return nil, fmt.Errorf("decode payload: %w", err)
+	}
+
+	return out, nil
+}
+
+// maskValue returns the masked copy of value at path. keep is false when a
+// MaskDrop rule removed the whole value.
+func (m masker) maskValue(value any, path []string) (masked any, keep bool) {
+	for _, rule := range m.rules {
+		if len(path) > 0 && rule.matchesPath(path) {
+			return m.apply(rule.Action, value)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, child := range v {
+			if masked, keep := m.maskValue(child, appendPath(path, key)); keep {
+				out[key] = masked
+			}
+		}
+		return out, true
+	case []any:
+		out := make([]any, 0, len(v))
+		for i, child := range v {
+			if masked, keep := m.maskValue(child, appendPath(path, fmt.Sprint(i))); keep {
+				out = append(out, masked)
+			}
+		}
+		return out, true
+	case string:
+		for _, rule := range m.rules {
+			v = m.maskString(rule, v)
+		}
+		return v, true
+	default:
+		return value, true
+	}
+}
+
+func appendPath(path []string, segment string) []string {
+	out := make([]string, len(path), len(path)+1)
+	copy(out, path)
+	return append(out, segment)
+}
+
+func (r MaskRule) matchesPath(path []string) bool {
+	last := path[len(path)-1]
+	for _, key := range r.Keys {
+		if strings.EqualFold(key, last) {
+			return true
+		}
+	}
+
+	for _, p := range r.Paths {
+		segments := strings.Split(p, ".")
+		if len(segments) != len(path) {
+			continue
+		}
+		matched := true
+		for i, segment := range segments {
+			if segment != "*" && segment != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m masker) maskString(r MaskRule, s string) string {
+	if r.Pattern == nil {
+		return s
+	}
+
+	return r.Pattern.ReplaceAllStringFunc(s, func(match string) string {
+		if r.Validate != nil && !r.Validate(match) {
+			return match
+		}
+		switch r.Action {
+		case MaskHash:
+			return m.hash(match)
+		case MaskDrop:
+			return ""
+		default:
+			return MaskRedacted
+		}
+	})
+}
+
+func (m masker) apply(a MaskAction, value any) (any, bool) {
+	switch a {
+	case MaskHash:
+		if s, ok := value.(string); ok {
+			return m.hash(s), true
+		}
+		data, _ := json.Marshal(value)
+		return m.hash(string(data)), true
+	case MaskDrop:
+		return nil, false
+	default:
+		return MaskRedacted, true
+	}
+}
+
+func (m masker) hash(s string) string {
+	mac := hmac.New(sha256.New, m.hashKey)
+	mac.Write([]byte(s))
+	return "hmac:" + hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+// luhnValid reports whether the digits of s pass the Luhn checksum.
+func luhnValid(s string) bool {
+	sum, double, digits := 0, false, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+		digits++
+	}
+	return digits >= 13 && sum%10 == 0
+}
+
+// maskEvents returns events with the payloads of traces and observations,
+// and the comments of scores, masked. Bodies are copied, so the caller's
+// structs are left untouched.
+func (l *Langfuse) maskEvents(events []model.IngestionEvent) []model.IngestionEvent {
+	if l.mask == nil {
+		return events
+	}
+
+	m := l.eventMask()
+	out := make([]model.IngestionEvent, len(events))
+	for i, event := range events {
+		m.maskEvent(&event)
+		out[i] = event
+	}
+
+	return out
+}
+
+func (l *Langfuse) eventMask() eventMask {
+	return eventMask{mask: l.mask, handleError: l.handleExportError}
+}
+
+// MaskProcessor returns an EventProcessor masking events with fn, for
+// masking that must happen before an event is queued: on the caller's
+// goroutine, ahead of the processors registered after it. Bodies are copied,
+// so the caller's structs are left untouched. Failures are handled as with
+// WithMask and passed to handleError; a nil handleError prints them.
+func MaskProcessor(fn MaskFunc, handleError func(error)) EventProcessor {
+	if handleError == nil {
+		handleError = func(err error) { fmt.Println(err) }
+	}
+	m := eventMask{mask: fn, handleError: handleError}
+
+	return EventProcessorFunc(func(_ context.Context, event *model.IngestionEvent) bool {
+		m.maskEvent(event)
+		return true
+	})
+}
+
+// eventMask applies a MaskFunc to events, passing failures to handleError.
+type eventMask struct {
+	mask        MaskFunc
+	handleError func(error)
+}
+
+// maskEvent replaces the body of event with a masked copy.
+func (m eventMask) maskEvent(event *model.IngestionEvent) {
+	switch body := event.Body.(type) {
+	case *model.Trace:
+		masked := *body
+		m.maskPayloads(&masked.Input, &masked.Output, &masked.Metadata)
+		event.Body = &masked
+	case *model.Generation:
+		masked := *body
+		m.maskPayloads(&masked.Input, &masked.Output, &masked.Metadata)
+		m.maskMessages(&masked.StatusMessage, &masked.Errors)
+		event.Body = &masked
+	case *model.Span:
+		masked := *body
+		m.maskPayloads(&masked.Input, &masked.Output, &masked.Metadata)
+		m.maskMessages(&masked.StatusMessage, &masked.Errors)
+		event.Body = &masked
+	case *model.Event:
+		masked := *body
+		m.maskPayloads(&masked.Input, &masked.Output, &masked.Metadata)
+		m.maskMessages(&masked.StatusMessage, &masked.Errors)
+		event.Body = &masked
+	case *model.Score:
+		masked := *body
+		masked.Comment = m.maskText(MaskFieldComment, masked.Comment)
+		event.Body = &masked
+	}
+}
+
+func (m eventMask) maskPayloads(input, output, metadata *any) {
+	*input = m.maskPayload(MaskFieldInput, *input)
+	*output = m.maskPayload(MaskFieldOutput, *output)
+	*metadata = m.maskPayload(MaskFieldMetadata, *metadata)
+}
+
+// maskMessages masks a status message and error messages. The errors are
+// copied.
+func (m eventMask) maskMessages(statusMessage *string, errs *[]model.ObservationError) {
+	*statusMessage = m.maskText(MaskFieldStatusMessage, *statusMessage)
+	if len(*errs) == 0 {
+		return
+	}
+
+	masked := make([]model.ObservationError, len(*errs))
+	for i, e := range *errs {
+		e.Message = m.maskText(MaskFieldError, e.Message)
+		masked[i] = e
+	}
+	*errs = masked
+}
+
+// maskText applies the mask to a message.
+func (m eventMask) maskText(field MaskField, s string) string {
+	if s == "" {
+		return ""
+	}
+
+	switch masked := m.maskPayload(field, s).(type) {
+	case nil:
+		return ""
+	case string:
+		return masked
+	default:
+		data, err := json.Marshal(masked)
+		if err != nil {
+			m.handleError(fmt.Errorf("mask %s: %w", field, err))
+			return MaskFailed
+		}
+		return string(data)
+	}
+}
+
+// maskPayload applies the mask to one payload. Any failure, including a
+// panic, yields MaskFailed rather than the original value.
+func (m eventMask) maskPayload(field MaskField, value any) (masked any) {
+	if value == nil {
+		return nil
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			m.handleError(fmt.Errorf("mask %s: panic: %v", field, r))
+			masked = MaskFailed
+		}
+	}()
+
+	masked, err := m.mask(field, value)
+	if err != nil {
+		m.handleError(fmt.Errorf("mask %s: %w", field, err))
+		return MaskFailed
+	}
+
+	return masked
+}
@@ -0,0 +1,182 @@
+package langfuse
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ezardev-team/langfuse-go/internal/pkg/api"
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+func TestNewMask_Patterns(t *testing.T) {
+	mask := NewMask(nil,
+		MaskCreditCards(MaskReplace),
+		MaskEmails(MaskReplace),
+		MaskPhoneNumbers(MaskReplace),
+		MaskBearerTokens(MaskDrop),
+	)
+
+	for in, want := range map[string]string{
+		"mail jane.doe@example.com now":              "mail [REDACTED] now",
+		"card 4111 1111 1111 1111 ok":                "card [REDACTED] ok",
+		"order 1234567890123 is not a card":          "order 1234567890123 is not a card",
+		"call +1 415-555-0132 today":                 "call [REDACTED] today",
+		"or (415) 555 0132":                          "or [REDACTED]",
+		"Authorization: Bearer abc.def-ghi_jkl==":    "Authorization: ",
+		"on 2026-01-02 nothing sensitive happened":   "on 2026-01-02 nothing sensitive happened",
+		"or 415.555.0132 or 415-555-0132":            "or [REDACTED] or [REDACTED]",
+		"intl +44 20 7946 0958":                      "intl [REDACTED]",
+		"trace 550e8400-e29b-41d4-a716-446655440000": "trace 550e8400-e29b-41d4-a716-446655440000",
+		"trace 12345678-1234-1234-1234-123456789012": "trace 12345678-1234-1234-1234-123456789012",
+		"batch 20240115 and 2024011512345":           "batch 20240115 and 2024011512345",
+	} {
+		got, err := mask(MaskFieldInput, in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("%q: got %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestNewMask_KeysAndPaths(t *testing.T) {
+	type message struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	}
+	payload := map[string]any{
+		"API_KEY":  "sk-123",
+		"user":     map[string]any{"email": "jane@example.com", "plan": "pro"},
+		"messages": []message{{Role: "user", Content: "secret"}, {Role: "assistant", Content: "reply"}},
+	}
+
+	mask := NewMask([]byte("test-key"),
+		MaskKeys(MaskDrop, "api_key"),
+		MaskPaths(MaskHash, "user.email"),
+		MaskPaths(MaskReplace, "messages.*.content"),
+	)
+	got, err := mask(MaskFieldMetadata, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := got.(map[string]any)
+	if _, ok := m["API_KEY"]; ok {
+		t.Error("API_KEY must be dropped")
+	}
+	user := m["user"].(map[string]any)
+	if email := user["email"].(string); !strings.HasPrefix(email, "hmac:") || email != (masker{hashKey: []byte("test-key")}).hash("jane@example.com") {
+		t.Errorf("email=%q, want a stable keyed hash", email)
+	}
+	if (masker{hashKey: []byte("other-key")}).hash("jane@example.com") == user["email"] {
+		t.Error("hashes must depend on the key")
+	}
+	if user["plan"] != "pro" {
+		t.Errorf("plan=%v, want unchanged", user["plan"])
+	}
+	for _, msg := range m["messages"].([]any) {
+		if c := msg.(map[string]any)["content"]; c != MaskRedacted {
+			t.Errorf("content=%v, want redacted", c)
+		}
+	}
+	if payload["API_KEY"] != "sk-123" {
+		t.Error("the caller's payload must not be modified")
+	}
+}
+
+func TestMaskPayload_FailureNeverLeaks(t *testing.T) {
+	var reported []error
+	l := (&Langfuse{}).WithExportErrorHandler(func(err error) { reported = append(reported, err) })
+
+	l.WithMask(func(MaskField, any) (any, error) { return nil, errors.New("bad rule") })
+	if got := l.eventMask().maskPayload(MaskFieldInput, "jane@example.com"); got != MaskFailed {
+		t.Errorf("error: got %v, want %q", got, MaskFailed)
+	}
+
+	l.WithMask(func(MaskField, any) (any, error) { panic("boom") })
+	if got := l.eventMask().maskPayload(MaskFieldOutput, "jane@example.com"); got != MaskFailed {
+		t.Errorf("panic: got %v, want %q", got, MaskFailed)
+	}
+
+	l.WithMask(NewMask(nil, MaskEmails(MaskReplace)))
+	if got := l.eventMask().maskPayload(MaskFieldMetadata, map[string]any{"ch": make(chan int)}); got != MaskFailed {
+		t.Errorf("unencodable: got %v, want %q", got, MaskFailed)
+	}
+
+	if len(reported) != 3 {
+		t.Errorf("reported=%v, want 3 errors", reported)
+	}
+}
+
+func TestIngest_MasksBeforeExport(t *testing.T) {
+	var body []byte
+	var scored bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = append(body, b...)
+		if r.URL.Path == "/api/public/scores" {
+			scored = true
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":"s-1"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	t.Setenv("LANGFUSE_HOST", srv.URL)
+
+	l := (&Langfuse{client: api.New()}).WithMask(NewMask(nil, MaskEmails(MaskReplace), MaskKeys(MaskReplace, "token")))
+	gen := &model.Generation{
+		ID: "g-1", TraceID: "t-1", Name: "gen",
+		Input:    "reply to jane@example.com",
+		Metadata: model.M{"token": "tok-secret"},
+	}
+	err := l.ingest(context.Background(), []model.IngestionEvent{
+		{Type: model.IngestionEventTypeTraceCreate, Body: &model.Trace{ID: "t-1", Name: "trace", Output: "jane@example.com"}},
+		{Type: model.IngestionEventTypeGenerationCreate, Body: gen},
+		{Type: model.IngestionEventTypeScoreCreate, Body: &model.Score{TraceID: "t-1", Name: "helpful", Value: 1, Comment: "jane@example.com liked it"}},
+	})
+	if err != nil {
+		t.Fatalf("ingest: %v", err)
+	}
+
+	if !scored {
+		t.Fatal("score was not posted")
+	}
+	if strings.Contains(string(body), "jane@example.com") || strings.Contains(string(body), "tok-secret") {
+		t.Error("export contains unmasked data")
+	}
+	if !strings.Contains(string(body), MaskRedacted) {
+		t.Error("export does not contain the redaction placeholder")
+	}
+	if gen.Input != "reply to jane@example.com" {
+		t.Error("the caller's generation must not be modified")
+	}
+}
+
+func TestMaskEvents_Messages(t *testing.T) {
+	l := (&Langfuse{}).WithMask(NewMask(nil, MaskEmails(MaskReplace)))
+	span := &model.Span{
+		ID: "s-1", TraceID: "t-1",
+		StatusMessage: "no mailbox for jane@example.com",
+		Errors:        []model.ObservationError{{Type: "*errors.errorString", Message: "send to jane@example.com: refused"}},
+	}
+
+	masked := l.maskEvents([]model.IngestionEvent{{Type: model.IngestionEventTypeSpanCreate, Body: span}})[0].Body.(*model.Span)
+
+	if want := "no mailbox for " + MaskRedacted; masked.StatusMessage != want {
+		t.Errorf("status message=%q, want %q", masked.StatusMessage, want)
+	}
+	if want := "send to " + MaskRedacted + ": refused"; masked.Errors[0].Message != want {
+		t.Errorf("error message=%q, want %q", masked.Errors[0].Message, want)
+	}
+	if span.StatusMessage != "no mailbox for jane@example.com" || span.Errors[0].Message != "send to jane@example.com: refused" {
+		t.Error("the caller's span must not be modified")
+	}
+}
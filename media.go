@@ -0,0 +1,264 @@
+package langfuse
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ezardev-team/langfuse-go/internal/pkg/api"
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+// Sources recorded in media reference strings.
+const (
+	MediaSourceDataURI = "base64_data_uri"
+	MediaSourceBytes   = "bytes"
+)
+
+// MediaReference is the string that replaces uploaded media in a payload.
+// The Langfuse UI renders it as the media it points to.
+type MediaReference struct {
+	ContentType string
+	MediaID     string
+	Source      string
+}
+
+// String formats the reference as
+// "@@@langfuseMedia:type=image/png|id=...|source=bytes@@@".
+func (r MediaReference) String() string {
+	return fmt.Sprintf("@@@langfuseMedia:type=%s|id=%s|source=%s@@@", r.ContentType, r.MediaID, r.Source)
+}
+
+// ParseMediaReference parses a string produced by MediaReference.String.
+func ParseMediaReference(s string) (MediaReference, bool) {
+	body, ok := strings.CutPrefix(s, "@@@langfuseMedia:")
+	if !ok {
+		return MediaReference{}, false
+	}
+	body, ok = strings.CutSuffix(body, "@@@")
+	if !ok {
+		return MediaReference{}, false
+	}
+
+	var ref MediaReference
+	for _, part := range strings.Split(body, "|") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "type":
+			ref.ContentType = value
+		case "id":
+			ref.MediaID = value
+		case "source":
+			ref.Source = value
+		}
+	}
+	if ref.ContentType == "" || ref.MediaID == "" || ref.Source == "" {
+		return MediaReference{}, false
+	}
+
+	return ref, true
+}
+
+// WithMediaUploads enables uploading media found in the input, output and
+// metadata of traces and observations: base64 data URIs, []byte values and
+// model.Media. Each is uploaded once per trace or observation field through
+// the Langfuse media API and replaced with a MediaReference string. Content
+// Langfuse already stores is linked without being uploaded again. When an
+// upload fails the error goes to the export error handler and the media is
+// sent inline. Uploads run on the masked payloads, so media a MaskFunc drops
+// or fails on is never uploaded; NewMask encodes []byte values as plain
+// base64, so with a mask set wrap them in model.Media to have them uploaded.
+func (l *Langfuse) WithMediaUploads(enabled bool) *Langfuse {
+	l.mediaUploads = enabled
+	return l
+}
+
+// mediaTarget is the trace or observation field media is linked to.
+type mediaTarget struct {
+	traceID       string
+	observationID string
+	field         string
+}
+
+// uploadMediaEvents returns events whose payloads have their media replaced
+// with references. Bodies are copied, never modified in place.
+func (l *Langfuse) uploadMediaEvents(ctx context.Context, events []model.IngestionEvent) []model.IngestionEvent {
+	if !l.mediaUploads {
+		return events
+	}
+
+	out := make([]model.IngestionEvent, len(events))
+	for i, event := range events {
+		switch body := event.Body.(type) {
+		case *model.Trace:
+			uploaded := *body
+			l.uploadMediaPayloads(ctx, body.ID, "", &uploaded.Input, &uploaded.Output, &uploaded.Metadata)
+			event.Body = &uploaded
+		case *model.Generation:
+			uploaded := *body
+			l.uploadMediaPayloads(ctx, body.TraceID, body.ID, &uploaded.Input, &uploaded.Output, &uploaded.Metadata)
+			event.Body = &uploaded
+		case *model.Span:
+			uploaded := *body
+			l.uploadMediaPayloads(ctx, body.TraceID, body.ID, &uploaded.Input, &uploaded.Output, &uploaded.Metadata)
+			event.Body = &uploaded
+		case *model.Event:
+			uploaded := *body
+			l.uploadMediaPayloads(ctx, body.TraceID, body.ID, &uploaded.Input, &uploaded.Output, &uploaded.Metadata)
+			event.Body = &uploaded
+		}
+		out[i] = event
+	}
+
+	return out
+}
+
+func (l *Langfuse) uploadMediaPayloads(ctx context.Context, traceID, observationID string, input, output, metadata *any) {
+	if traceID == "" {
+		return
+	}
+	*input = l.replaceMedia(ctx, mediaTarget{traceID, observationID, "input"}, *input)
+	*output = l.replaceMedia(ctx, mediaTarget{traceID, observationID, "output"}, *output)
+	*metadata = l.replaceMedia(ctx, mediaTarget{traceID, observationID, "metadata"}, *metadata)
+}
+
+// replaceMedia walks value and replaces the media it finds. Maps and slices
+// are copied; other values that marshal to JSON containing a data URI are
+// replaced by their decoded JSON form.
+func (l *Langfuse) replaceMedia(ctx context.Context, target mediaTarget, value any) any {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		if media, ok := model.ParseDataURI(v); ok {
+			return l.mediaReference(ctx, target, media, MediaSourceDataURI, v)
+		}
+		return v
+	case []byte:
+		return l.mediaReference(ctx, target, model.NewMedia(v), MediaSourceBytes, v)
+	case model.Media:
+		return l.mediaReference(ctx, target, &v, MediaSourceBytes, v)
+	case *model.Media:
+		if v == nil {
+			return nil
+		}
+		return l.mediaReference(ctx, target, v, MediaSourceBytes, v)
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, child := range v {
+			out[key] = l.replaceMedia(ctx, target, child)
+		}
+		return out
+	case model.M:
+		return l.replaceMedia(ctx, target, map[string]any(v))
+	case []any:
+		out := make([]any, len(v))
+		for i, child := range v {
+			out[i] = l.replaceMedia(ctx, target, child)
+		}
+		return out
+	case bool, int, int64, float64, json.Number:
+		return v
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil || !bytes.Contains(encoded, []byte(`"data:`)) {
+		return value
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var tree any
+	if err := decoder.Decode(&tree); err != nil {
+		return value
+	}
+
+	return l.replaceMedia(ctx, target, tree)
+}
+
+// mediaReference uploads media and returns its reference string, or
+// fallback when the upload fails.
+func (l *Langfuse) mediaReference(ctx context.Context, target mediaTarget, media *model.Media, source string, fallback any) any {
+	mediaID, err := l.uploadMedia(ctx, target, media)
+	if err != nil {
+		l.handleExportError(fmt.Errorf("media %s: %w", target.field, err))
+		return fallback
+	}
+
+	return MediaReference{ContentType: media.MediaType(), MediaID: mediaID, Source: source}.String()
+}
+
+// uploadMedia links media to target and uploads it when Langfuse does not
+// store it yet. Media recently linked to the same target is not sent again;
+// older links are forgotten and only cost a request to Langfuse, which
+// skips the upload of content it already stores.
+func (l *Langfuse) uploadMedia(ctx context.Context, target mediaTarget, media *model.Media) (string, error) {
+	sum := sha256.Sum256(media.Data)
+	hash := base64.StdEncoding.EncodeToString(sum[:])
+
+	cacheKey := target.traceID + "\x00" + target.observationID + "\x00" + target.field + "\x00" + hash
+	if mediaID, ok := l.mediaIDs.get(cacheKey); ok {
+		return mediaID, nil
+	}
+
+	req := &api.MediaUploadRequest{
+		TraceID:       target.traceID,
+		ObservationID: target.observationID,
+		MediaType:     media.MediaType(),
+		ContentLength: len(media.Data),
+		SHA256Hash:    hash,
+		Field:         target.field,
+	}
+	res := api.MediaUploadResponse{}
+	if err := l.client.CreateMediaUpload(ctx, req, &res); err != nil {
+		return "", err
+	}
+	if !res.IsSuccess() {
+		return "", newAPIError("media", req, res.Code, res.RawBody)
+	}
+
+	if res.UploadURL != nil {
+		if err := l.putMedia(ctx, res.MediaID, *res.UploadURL, req, media.Data); err != nil {
+			return "", err
+		}
+	}
+
+	l.mediaIDs.set(cacheKey, res.MediaID)
+	return res.MediaID, nil
+}
+
+// putMedia uploads data to uploadURL and reports the outcome to Langfuse.
+func (l *Langfuse) putMedia(ctx context.Context, mediaID, uploadURL string, req *api.MediaUploadRequest, data []byte) error {
+	start := time.Now()
+	status, uploadErr := l.client.UploadMedia(ctx, uploadURL, req.MediaType, req.SHA256Hash, data)
+	l.exportStats.update(func(s *ExportStats) {
+		s.MediaUploads++
+		if uploadErr != nil {
+			s.FailedMediaUploads++
+		}
+	})
+
+	patch := &api.MediaPatchRequest{
+		MediaID:          mediaID,
+		UploadedAt:       time.Now().UTC(),
+		UploadHTTPStatus: status,
+		UploadTimeMs:     time.Since(start).Milliseconds(),
+	}
+	if uploadErr != nil {
+		patch.UploadHTTPError = uploadErr.Error()
+	}
+	res := api.MediaPatchResponse{}
+	err := l.client.UpdateMedia(ctx, patch, &res)
+	if err == nil && !res.IsSuccess() {
+		err = newAPIError("media", patch, res.Code, res.RawBody)
+	}
+
+	if uploadErr != nil {
+		return uploadErr
+	}
+	return err
+}
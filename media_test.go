@@ -0,0 +1,248 @@
+package langfuse
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ezardev-team/langfuse-go/internal/pkg/api"
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+// mediaStandIn emulates the Langfuse media API and a presigned upload
+// target. Content is stored once per hash; later requests for the same hash
+// get no upload URL.
+type mediaStandIn struct {
+	mu       sync.Mutex
+	requests []api.MediaUploadRequest
+	uploads  map[string][]byte // media ID -> content
+	patches  []map[string]any
+	failPuts bool
+	mediaIDs map[string]string // hash -> media ID
+}
+
+func newMediaStandIn(t *testing.T) *mediaStandIn {
+	t.Helper()
+
+	m := &mediaStandIn{uploads: map[string][]byte{}, mediaIDs: map[string]string{}}
+	var srv *httptest.Server
+	srv = newStandIn(t, func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/public/media":
+			var req api.MediaUploadRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("decode media request: %v", err)
+			}
+			m.requests = append(m.requests, req)
+
+			res := map[string]any{"uploadUrl": nil}
+			mediaID, ok := m.mediaIDs[req.SHA256Hash]
+			if !ok {
+				mediaID = "media-" + string(rune('a'+len(m.mediaIDs)))
+				m.mediaIDs[req.SHA256Hash] = mediaID
+				res["uploadUrl"] = srv.URL + "/upload/" + mediaID
+			}
+			res["mediaId"] = mediaID
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(res)
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/upload/"):
+			if r.Header.Get("Authorization") != "" {
+				t.Error("upload must not carry Langfuse credentials")
+			}
+			if m.failPuts {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			data, _ := io.ReadAll(r.Body)
+			sum := sha256.Sum256(data)
+			if got := r.Header.Get("x-amz-checksum-sha256"); got != base64.StdEncoding.EncodeToString(sum[:]) {
+				t.Errorf("checksum header=%q", got)
+			}
+			m.uploads[strings.TrimPrefix(r.URL.Path, "/upload/")] = data
+		case r.Method == http.MethodPatch && strings.HasPrefix(r.URL.Path, "/api/public/media/"):
+			var patch map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&patch)
+			patch["mediaId"] = strings.TrimPrefix(r.URL.Path, "/api/public/media/")
+			m.patches = append(m.patches, patch)
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/api/public/otel/v1/traces":
+			w.WriteHeader(http.StatusOK)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	return m
+}
+
+func TestUploadMediaEvents(t *testing.T) {
+	standIn := newMediaStandIn(t)
+	png := []byte("\x89PNG\r\n\x1a\n-image-")
+	dataURI := "data:image/png;base64," + base64.StdEncoding.EncodeToString(png)
+	audio := []byte("ID3-audio")
+
+	input := map[string]any{"text": "describe this", "images": []any{dataURI, dataURI}}
+	trace := &model.Trace{ID: "t-1", Input: input, Output: "plain"}
+	generation := &model.Generation{
+		ID: "g-1", TraceID: "t-1",
+		Input:    []any{model.M{"image": dataURI}},
+		Output:   &model.Media{ContentType: "audio/mpeg", Data: audio},
+		Metadata: model.M{"raw": png},
+	}
+
+	l := (&Langfuse{client: api.New()}).WithMediaUploads(true)
+	events := l.uploadMediaEvents(context.Background(), []model.IngestionEvent{
+		{Type: model.IngestionEventTypeTraceCreate, Body: trace},
+		{Type: model.IngestionEventTypeGenerationCreate, Body: generation},
+	})
+
+	if input["images"].([]any)[0] != dataURI {
+		t.Error("caller payload was modified")
+	}
+
+	uploadedTrace := events[0].Body.(*model.Trace)
+	images := uploadedTrace.Input.(map[string]any)["images"].([]any)
+	ref, ok := ParseMediaReference(images[0].(string))
+	if !ok || ref.ContentType != "image/png" || ref.Source != MediaSourceDataURI || images[1] != images[0] {
+		t.Errorf("trace images=%v", images)
+	}
+	if uploadedTrace.Output != "plain" {
+		t.Errorf("output=%v, want unchanged", uploadedTrace.Output)
+	}
+
+	uploadedGeneration := events[1].Body.(*model.Generation)
+	genImage := uploadedGeneration.Input.([]any)[0].(map[string]any)["image"]
+	if genRef, _ := ParseMediaReference(genImage.(string)); genRef.MediaID != ref.MediaID {
+		t.Errorf("generation image=%v, want media %s", genImage, ref.MediaID)
+	}
+	if audioRef, ok := ParseMediaReference(uploadedGeneration.Output.(string)); !ok || audioRef.ContentType != "audio/mpeg" || audioRef.Source != MediaSourceBytes {
+		t.Errorf("generation output=%v", uploadedGeneration.Output)
+	}
+	if _, ok := ParseMediaReference(uploadedGeneration.Metadata.(map[string]any)["raw"].(string)); !ok {
+		t.Errorf("generation metadata=%v", uploadedGeneration.Metadata)
+	}
+
+	standIn.mu.Lock()
+	defer standIn.mu.Unlock()
+	// The image is linked once per field (trace input, generation input and
+	// metadata) but stored once; the audio is new content.
+	if len(standIn.requests) != 4 {
+		t.Fatalf("media requests=%+v, want 4", standIn.requests)
+	}
+	if req := standIn.requests[1]; req.TraceID != "t-1" || req.ObservationID != "g-1" || req.Field != "input" || req.ContentLength != len(png) {
+		t.Errorf("generation request=%+v", req)
+	}
+	if len(standIn.uploads) != 2 || string(standIn.uploads[ref.MediaID]) != string(png) {
+		t.Errorf("uploads=%v, want each content once", standIn.uploads)
+	}
+	if len(standIn.patches) != 2 || standIn.patches[0]["uploadHttpStatus"] != float64(http.StatusOK) {
+		t.Errorf("patches=%v", standIn.patches)
+	}
+	if stats := l.Stats(); stats.MediaUploads != 2 || stats.FailedMediaUploads != 0 {
+		t.Errorf("stats=%+v", stats)
+	}
+}
+
+func TestUploadMediaEvents_Failure(t *testing.T) {
+	standIn := newMediaStandIn(t)
+	standIn.failPuts = true
+	dataURI := "data:image/png;base64," + base64.StdEncoding.EncodeToString([]byte("png"))
+
+	var errs []error
+	l := (&Langfuse{client: api.New()}).WithMediaUploads(true).WithExportErrorHandler(func(err error) {
+		errs = append(errs, err)
+	})
+	events := l.uploadMediaEvents(context.Background(), []model.IngestionEvent{
+		{Type: model.IngestionEventTypeSpanCreate, Body: &model.Span{ID: "s-1", TraceID: "t-1", Input: dataURI}},
+	})
+
+	if got := events[0].Body.(*model.Span).Input; got != dataURI {
+		t.Errorf("input=%v, want the data URI sent inline", got)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "status 403") {
+		t.Errorf("errs=%v", errs)
+	}
+
+	standIn.mu.Lock()
+	defer standIn.mu.Unlock()
+	if len(standIn.patches) != 1 || standIn.patches[0]["uploadHttpStatus"] != float64(http.StatusForbidden) || standIn.patches[0]["uploadHttpError"] == nil {
+		t.Errorf("patches=%v", standIn.patches)
+	}
+	if stats := l.Stats(); stats.FailedMediaUploads != 1 {
+		t.Errorf("stats=%+v", stats)
+	}
+}
+
+func TestIngest_MaskedMediaIsNotUploaded(t *testing.T) {
+	standIn := newMediaStandIn(t)
+	dataURI := "data:image/png;base64," + base64.StdEncoding.EncodeToString([]byte("\x89PNG\r\n\x1a\n-image-"))
+
+	drop := NewMask(nil, MaskKeys(MaskDrop, "image"))
+	var errs []error
+	l := (&Langfuse{client: api.New()}).WithMediaUploads(true).WithMask(func(field MaskField, value any) (any, error) {
+		if field == MaskFieldOutput {
+			return nil, errors.New("mask failed")
+		}
+		return drop(field, value)
+	}).WithExportErrorHandler(func(err error) {
+		errs = append(errs, err)
+	})
+	err := l.ingest(context.Background(), []model.IngestionEvent{{
+		Type: model.IngestionEventTypeSpanCreate,
+		Body: &model.Span{
+			ID: "s-1", TraceID: "t-1",
+			Input:    model.M{"text": "describe this", "image": dataURI},
+			Output:   dataURI,
+			Metadata: model.M{"thumbnail": dataURI},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("ingest: %v", err)
+	}
+	if len(errs) != 1 {
+		t.Errorf("errs=%v, want the mask failure", errs)
+	}
+
+	standIn.mu.Lock()
+	defer standIn.mu.Unlock()
+	// Only the metadata thumbnail survives the mask.
+	if len(standIn.requests) != 1 || standIn.requests[0].Field != "metadata" {
+		t.Errorf("media requests=%+v, want the metadata only", standIn.requests)
+	}
+	if len(standIn.uploads) != 1 {
+		t.Errorf("uploads=%d, want 1", len(standIn.uploads))
+	}
+}
+
+func TestUploadMediaEvents_Disabled(t *testing.T) {
+	l := &Langfuse{}
+	events := []model.IngestionEvent{{Type: model.IngestionEventTypeSpanCreate, Body: &model.Span{Input: []byte("x")}}}
+	if got := l.uploadMediaEvents(context.Background(), events); &got[0] != &events[0] {
+		t.Error("events must pass through when uploads are disabled")
+	}
+}
+
+func TestParseMediaReference(t *testing.T) {
+	ref := MediaReference{ContentType: "image/jpeg", MediaID: "abc", Source: MediaSourceBytes}
+	if got := ref.String(); got != "@@@langfuseMedia:type=image/jpeg|id=abc|source=bytes@@@" {
+		t.Errorf("String()=%s", got)
+	}
+	if parsed, ok := ParseMediaReference(ref.String()); !ok || parsed != ref {
+		t.Errorf("parsed=%+v ok=%v", parsed, ok)
+	}
+	if _, ok := ParseMediaReference("@@@langfuseMedia:type=image/jpeg@@@"); ok {
+		t.Error("incomplete reference must not parse")
+	}
+}
@@ -0,0 +1,352 @@
+package langfuse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ezardev-team/langfuse-go/internal/pkg/api"
+)
+
+// MetricsView is the entity a metrics query aggregates over.
+type MetricsView string
+
+const (
+	MetricsViewTraces            MetricsView = "traces"
+	MetricsViewObservations      MetricsView = "observations"
+	MetricsViewScoresNumeric     MetricsView = "scores-numeric"
+	MetricsViewScoresCategorical MetricsView = "scores-categorical"
+)
+
+// MetricsAggregation is how a measure is aggregated within a row.
+type MetricsAggregation string
+
+const (
+	MetricsAggregationCount MetricsAggregation = "count"
+	MetricsAggregationSum   MetricsAggregation = "sum"
+	MetricsAggregationAvg   MetricsAggregation = "avg"
+	MetricsAggregationMin   MetricsAggregation = "min"
+	MetricsAggregationMax   MetricsAggregation = "max"
+	MetricsAggregationP50   MetricsAggregation = "p50"
+	MetricsAggregationP75   MetricsAggregation = "p75"
+	MetricsAggregationP90   MetricsAggregation = "p90"
+	MetricsAggregationP95   MetricsAggregation = "p95"
+	MetricsAggregationP99   MetricsAggregation = "p99"
+)
+
+// Common measures. Which ones are available depends on the view; any
+// measure name accepted by the API may be used.
+const (
+	MetricsMeasureCount            = "count"
+	MetricsMeasureLatency          = "latency"
+	MetricsMeasureTotalCost        = "totalCost"
+	MetricsMeasureTotalTokens      = "totalTokens"
+	MetricsMeasureInputTokens      = "inputTokens"
+	MetricsMeasureOutputTokens     = "outputTokens"
+	MetricsMeasureTimeToFirstToken = "timeToFirstToken"
+	MetricsMeasureValue            = "value"
+)
+
+// MetricsGranularity buckets rows by time.
+type MetricsGranularity string
+
+const (
+	MetricsGranularityMinute MetricsGranularity = "minute"
+	MetricsGranularityHour   MetricsGranularity = "hour"
+	MetricsGranularityDay    MetricsGranularity = "day"
+	MetricsGranularityWeek   MetricsGranularity = "week"
+	MetricsGranularityMonth  MetricsGranularity = "month"
+	MetricsGranularityAuto   MetricsGranularity = "auto"
+)
+
+// MetricsTimeField is the row field of the time bucket.
+const MetricsTimeField = "time_dimension"
+
+// Metric is one aggregated measure, e.g. the p95 of latency.
+type Metric struct {
+	Measure     string             `json:"measure"`
+	Aggregation MetricsAggregation `json:"aggregation"`
+}
+
+// Key returns the row key the metric is reported under, e.g. "p95_latency".
+func (m Metric) Key() string {
+	return string(m.Aggregation) + "_" + m.Measure
+}
+
+// MetricsOrder sorts rows by a dimension or metric key.
+type MetricsOrder struct {
+	Field string
+	Desc  bool
+}
+
+// MetricsQuery is a query for QueryMetrics.
+type MetricsQuery struct {
+	View MetricsView
+	// Dimensions groups rows by these fields, e.g. "name" or "providedModelName".
+	Dimensions []string
+	Metrics    []Metric
+	// Filters restricts the rows aggregated, built from typed columns such as
+	// StringColumn("userId").Eq("u-1").
+	Filters []FilterCondition
+	// Granularity adds a time bucket to every row when set.
+	Granularity MetricsGranularity
+	// From and To bound the time range and are required.
+	From time.Time
+	To   time.Time
+	// OrderBy sorts rows; fields are dimension names or Metric.Key values.
+	OrderBy []MetricsOrder
+	// RowLimit caps the number of rows. Zero uses the API default.
+	RowLimit int
+}
+
+type metricsQueryJSON struct {
+	View          MetricsView           `json:"view"`
+	Dimensions    []metricsFieldJSON    `json:"dimensions"`
+	Metrics       []Metric              `json:"metrics"`
+	Filters       []FilterCondition     `json:"filters"`
+	TimeDimension *metricsTimeDimension `json:"timeDimension,omitempty"`
+	FromTimestamp string                `json:"fromTimestamp"`
+	ToTimestamp   string                `json:"toTimestamp"`
+	OrderBy       []metricsOrderJSON    `json:"orderBy,omitempty"`
+	Config        *metricsConfigJSON    `json:"config,omitempty"`
+}
+
+type metricsFieldJSON struct {
+	Field string `json:"field"`
+}
+
+type metricsTimeDimension struct {
+	Granularity MetricsGranularity `json:"granularity"`
+}
+
+type metricsOrderJSON struct {
+	Field     string `json:"field"`
+	Direction string `json:"direction"`
+}
+
+type metricsConfigJSON struct {
+	RowLimit int `json:"row_limit,omitempty"`
+}
+
+// encode validates q and returns the JSON query parameter.
+func (q *MetricsQuery) encode() (string, error) {
+	if q.View == "" {
+		return "", fmt.Errorf("metrics query: view is required")
+	}
+	if len(q.Metrics) == 0 {
+		return "", fmt.Errorf("metrics query: at least one metric is required")
+	}
+	for _, m := range q.Metrics {
+		if m.Measure == "" || m.Aggregation == "" {
+			return "", fmt.Errorf("metrics query: metric %+v needs a measure and an aggregation", m)
+		}
+	}
+	if q.From.IsZero() || q.To.IsZero() {
+		return "", fmt.Errorf("metrics query: time range is required")
+	}
+	if !q.From.Before(q.To) {
+		return "", fmt.Errorf("metrics query: From must be before To")
+	}
+	for _, c := range q.Filters {
+		if err := c.Validate(); err != nil {
+			return "", err
+		}
+	}
+
+	body := metricsQueryJSON{
+		View:          q.View,
+		Dimensions:    make([]metricsFieldJSON, 0, len(q.Dimensions)),
+		Metrics:       q.Metrics,
+		Filters:       q.Filters,
+		FromTimestamp: q.From.UTC().Format(time.RFC3339Nano),
+		ToTimestamp:   q.To.UTC().Format(time.RFC3339Nano),
+	}
+	if body.Filters == nil {
+		body.Filters = []FilterCondition{}
+	}
+	for _, d := range q.Dimensions {
+		body.Dimensions = append(body.Dimensions, metricsFieldJSON{Field: d})
+	}
+	if q.Granularity != "" {
+		body.TimeDimension = &metricsTimeDimension{Granularity: q.Granularity}
+	}
+	for _, o := range q.OrderBy {
+		direction := "asc"
+		if o.Desc {
+			direction = "desc"
+		}
+		body.OrderBy = append(body.OrderBy, metricsOrderJSON{Field: o.Field, Direction: direction})
+	}
+	if q.RowLimit > 0 {
+		body.Config = &metricsConfigJSON{RowLimit: q.RowLimit}
+	}
+
+	data, err := marshalFilterJSON(body)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode metrics query: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// MetricsRow is one row of a metrics result.
+type MetricsRow struct {
+	// Dimensions holds the value of every query dimension by field name.
+	// Null values are "".
+	Dimensions map[string]string
+	// Metrics holds the value of every query metric by Metric.Key. The API
+	// reports some aggregates as strings; those are parsed. Metrics without
+	// a numeric value in the row are absent.
+	Metrics map[string]float64
+	// Time is the start of the row's time bucket when the query set a
+	// Granularity, and zero otherwise.
+	Time time.Time
+}
+
+// Value returns the value of metric m and whether the row has one.
+func (r MetricsRow) Value(m Metric) (value float64, ok bool) {
+	value, ok = r.Metrics[m.Key()]
+	return value, ok
+}
+
+func newMetricsRow(query *MetricsQuery, data map[string]any) (MetricsRow, error) {
+	row := MetricsRow{
+		Dimensions: make(map[string]string, len(query.Dimensions)),
+		Metrics:    make(map[string]float64, len(query.Metrics)),
+	}
+
+	for _, field := range query.Dimensions {
+		switch v := data[field].(type) {
+		case nil:
+			row.Dimensions[field] = ""
+		case string:
+			row.Dimensions[field] = v
+		default:
+			row.Dimensions[field] = fmt.Sprint(v)
+		}
+	}
+
+	for _, m := range query.Metrics {
+		var (
+			value float64
+			err   error
+		)
+		switch v := data[m.Key()].(type) {
+		case nil:
+			continue
+		case json.Number:
+			value, err = v.Float64()
+		case float64:
+			value = v
+		case string:
+			value, err = strconv.ParseFloat(v, 64)
+		default:
+			err = fmt.Errorf("unexpected %T", v)
+		}
+		if err != nil {
+			return MetricsRow{}, fmt.Errorf("metrics row: %s: %w", m.Key(), err)
+		}
+		row.Metrics[m.Key()] = value
+	}
+
+	if query.Granularity != "" {
+		s, _ := data[MetricsTimeField].(string)
+		t, err := parseMetricsTime(s)
+		if err != nil {
+			return MetricsRow{}, err
+		}
+		row.Time = t
+	}
+
+	return row, nil
+}
+
+func parseMetricsTime(s string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("metrics row: unexpected time bucket %q", s)
+}
+
+// QueryMetrics runs an aggregate query against the Langfuse metrics API.
+//
+//	rows, err := l.QueryMetrics(ctx, langfuse.MetricsQuery{
+//		View:        langfuse.MetricsViewObservations,
+//		Dimensions:  []string{"providedModelName"},
+//		Metrics:     []langfuse.Metric{{Measure: langfuse.MetricsMeasureTotalCost, Aggregation: langfuse.MetricsAggregationSum}},
+//		Granularity: langfuse.MetricsGranularityDay,
+//		From:        time.Now().AddDate(0, 0, -7),
+//		To:          time.Now(),
+//	})
+func (l *Langfuse) QueryMetrics(ctx context.Context, query MetricsQuery) ([]MetricsRow, error) {
+	encoded, err := query.encode()
+	if err != nil {
+		return nil, err
+	}
+
+	req := api.MetricsRequest{Query: encoded}
+	res := api.MetricsResponse{}
+
+	if err := l.client.Metrics(ctx, &req, &res); err != nil {
+		return nil, err
+	}
+
+	if !res.IsSuccess() {
+		return nil, newAPIError("metrics", &req, res.Code, res.RawBody)
+	}
+
+	rows := make([]MetricsRow, len(res.Data))
+	for i, data := range res.Data {
+		if rows[i], err = newMetricsRow(&query, data); err != nil {
+			return nil, err
+		}
+	}
+
+	return rows, nil
+}
+
+// QueryMetricsInto runs query like QueryMetrics and decodes every row into a
+// T. Fields of T are matched by their json tags: dimension fields by field
+// name, metrics by Metric.Key and the time bucket by MetricsTimeField.
+// Metric values are numbers and the time bucket is a time.Time.
+//
+//	type modelCost struct {
+//		Model string    `json:"providedModelName"`
+//		Cost  float64   `json:"sum_totalCost"`
+//		Day   time.Time `json:"time_dimension"`
+//	}
+//	rows, err := langfuse.QueryMetricsInto[modelCost](ctx, l, query)
+func QueryMetricsInto[T any](ctx context.Context, l *Langfuse, query MetricsQuery) ([]T, error) {
+	rows, err := l.QueryMetrics(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]T, len(rows))
+	for i, row := range rows {
+		fields := make(map[string]any, len(row.Dimensions)+len(row.Metrics)+1)
+		for field, value := range row.Dimensions {
+			fields[field] = value
+		}
+		for key, value := range row.Metrics {
+			fields[key] = value
+		}
+		if query.Granularity != "" {
+			fields[MetricsTimeField] = row.Time
+		}
+
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &out[i]); err != nil {
+			return nil, fmt.Errorf("metrics row %d: %w", i, err)
+		}
+	}
+
+	return out, nil
+}
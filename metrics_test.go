@@ -0,0 +1,148 @@
+package langfuse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestQueryMetrics(t *testing.T) {
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	cost := Metric{Measure: MetricsMeasureTotalCost, Aggregation: MetricsAggregationSum}
+	latency := Metric{Measure: MetricsMeasureLatency, Aggregation: MetricsAggregationP95}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/public/metrics" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		var q map[string]any
+		if err := json.Unmarshal([]byte(r.URL.Query().Get("query")), &q); err != nil {
+			t.Fatalf("decode query: %v", err)
+		}
+		if q["view"] != "observations" || q["fromTimestamp"] != "2024-05-01T00:00:00Z" || q["toTimestamp"] != "2024-05-08T00:00:00Z" {
+			t.Errorf("query=%v", q)
+		}
+		if dims := q["dimensions"].([]any); len(dims) != 1 || dims[0].(map[string]any)["field"] != "providedModelName" {
+			t.Errorf("dimensions=%v", q["dimensions"])
+		}
+		if metrics := q["metrics"].([]any); len(metrics) != 2 || metrics[1].(map[string]any)["aggregation"] != "p95" {
+			t.Errorf("metrics=%v", q["metrics"])
+		}
+		if filters := q["filters"].([]any); len(filters) != 1 || filters[0].(map[string]any)["column"] != "environment" {
+			t.Errorf("filters=%v", q["filters"])
+		}
+		if q["timeDimension"].(map[string]any)["granularity"] != "day" {
+			t.Errorf("timeDimension=%v", q["timeDimension"])
+		}
+		if order := q["orderBy"].([]any); order[0].(map[string]any)["direction"] != "desc" {
+			t.Errorf("orderBy=%v", q["orderBy"])
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": [
+			{"providedModelName": "gpt-4o", "time_dimension": "2024-05-01T00:00:00.000Z", "sum_totalCost": 12.5, "p95_latency": "1834.2"},
+			{"providedModelName": null, "time_dimension": "2024-05-02T00:00:00.000Z", "sum_totalCost": 0.25}
+		]}`))
+	}))
+	defer srv.Close()
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	rows, err := l.QueryMetrics(context.Background(), MetricsQuery{
+		View:        MetricsViewObservations,
+		Dimensions:  []string{"providedModelName"},
+		Metrics:     []Metric{cost, latency},
+		Filters:     []FilterCondition{ObservationColumnEnvironment.AnyOf("production")},
+		Granularity: MetricsGranularityDay,
+		From:        from,
+		To:          to,
+		OrderBy:     []MetricsOrder{{Field: cost.Key(), Desc: true}},
+	})
+	if err != nil {
+		t.Fatalf("QueryMetrics: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("rows=%d, want 2", len(rows))
+	}
+
+	if got := rows[0].Dimensions["providedModelName"]; got != "gpt-4o" {
+		t.Errorf("model=%q", got)
+	}
+	if v, ok := rows[0].Value(cost); !ok || v != 12.5 {
+		t.Errorf("cost=%v ok=%v", v, ok)
+	}
+	if v := rows[0].Metrics[latency.Key()]; v != 1834.2 {
+		t.Errorf("string-encoded p95 latency=%v", v)
+	}
+	if !rows[0].Time.Equal(from) {
+		t.Errorf("time=%v", rows[0].Time)
+	}
+
+	if got, ok := rows[1].Dimensions["providedModelName"]; !ok || got != "" {
+		t.Errorf("null dimension=%q, want empty", got)
+	}
+	if _, ok := rows[1].Value(latency); ok {
+		t.Error("missing metric must report ok=false")
+	}
+}
+
+func TestQueryMetricsInto(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data": [
+			{"name": "chat", "environment": "production", "time_dimension": "2024-05-01 00:00:00", "count_count": "42", "avg_latency": 812.5}
+		]}`))
+	}))
+	defer srv.Close()
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	type traceStats struct {
+		Name    string    `json:"name"`
+		Count   int       `json:"count_count"`
+		Latency float64   `json:"avg_latency"`
+		Day     time.Time `json:"time_dimension"`
+	}
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	rows, err := QueryMetricsInto[traceStats](context.Background(), l, MetricsQuery{
+		View:        MetricsViewTraces,
+		Dimensions:  []string{"name", "environment"},
+		Metrics:     []Metric{{Measure: MetricsMeasureCount, Aggregation: MetricsAggregationCount}, {Measure: MetricsMeasureLatency, Aggregation: MetricsAggregationAvg}},
+		Granularity: MetricsGranularityDay,
+		From:        from,
+		To:          from.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		t.Fatalf("QueryMetricsInto: %v", err)
+	}
+	want := traceStats{Name: "chat", Count: 42, Latency: 812.5, Day: from}
+	if len(rows) != 1 || rows[0] != want {
+		t.Errorf("rows=%+v, want %+v", rows, want)
+	}
+}
+
+func TestMetricsQuery_Validation(t *testing.T) {
+	now := time.Now()
+	count := Metric{Measure: MetricsMeasureCount, Aggregation: MetricsAggregationCount}
+
+	tests := map[string]MetricsQuery{
+		"no view":        {Metrics: []Metric{count}, From: now.Add(-time.Hour), To: now},
+		"no metrics":     {View: MetricsViewTraces, From: now.Add(-time.Hour), To: now},
+		"partial metric": {View: MetricsViewTraces, Metrics: []Metric{{Measure: "count"}}, From: now.Add(-time.Hour), To: now},
+		"no range":       {View: MetricsViewTraces, Metrics: []Metric{count}},
+		"reversed range": {View: MetricsViewTraces, Metrics: []Metric{count}, From: now, To: now.Add(-time.Hour)},
+		"bad filter":     {View: MetricsViewTraces, Metrics: []Metric{count}, From: now.Add(-time.Hour), To: now, Filters: []FilterCondition{{}}},
+	}
+	for name, q := range tests {
+		if _, err := q.encode(); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
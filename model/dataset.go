@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+type DatasetStatus string
+
+const (
+	DatasetStatusActive   DatasetStatus = "ACTIVE"
+	DatasetStatusArchived DatasetStatus = "ARCHIVED"
+)
+
+// Dataset is decoded from `GET /api/public/v2/datasets`.
+type Dataset struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Metadata    any        `json:"metadata,omitempty"`
+	ProjectID   string     `json:"projectId,omitempty"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt   *time.Time `json:"updatedAt,omitempty"`
+}
+
+// DatasetItem is decoded from `GET /api/public/dataset-items`.
+type DatasetItem struct {
+	ID                  string        `json:"id"`
+	DatasetID           string        `json:"datasetId,omitempty"`
+	DatasetName         string        `json:"datasetName,omitempty"`
+	Input               any           `json:"input,omitempty"`
+	ExpectedOutput      any           `json:"expectedOutput,omitempty"`
+	Metadata            any           `json:"metadata,omitempty"`
+	SourceTraceID       string        `json:"sourceTraceId,omitempty"`
+	SourceObservationID string        `json:"sourceObservationId,omitempty"`
+	Status              DatasetStatus `json:"status,omitempty"`
+	CreatedAt           *time.Time    `json:"createdAt,omitempty"`
+	UpdatedAt           *time.Time    `json:"updatedAt,omitempty"`
+}
+
+// DatasetRunItem links a dataset item to the trace produced for it in an
+// experiment run. It is returned by `POST /api/public/dataset-run-items`.
+type DatasetRunItem struct {
+	ID             string     `json:"id"`
+	DatasetRunID   string     `json:"datasetRunId,omitempty"`
+	DatasetRunName string     `json:"datasetRunName,omitempty"`
+	DatasetItemID  string     `json:"datasetItemId,omitempty"`
+	TraceID        string     `json:"traceId,omitempty"`
+	ObservationID  string     `json:"observationId,omitempty"`
+	CreatedAt      *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt      *time.Time `json:"updatedAt,omitempty"`
+}
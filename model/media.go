@@ -0,0 +1,66 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Media is binary content, such as an image or an audio clip, placed in a
+// trace or observation payload. When media uploads are enabled it is
+// uploaded to Langfuse and replaced with a reference string; otherwise it is
+// sent inline as a base64 data URI.
+type Media struct {
+	// ContentType is the MIME type, e.g. "image/png". When empty it is
+	// sniffed from Data.
+	ContentType string
+	Data        []byte
+}
+
+// NewMedia returns Media holding data with its sniffed content type.
+func NewMedia(data []byte) *Media {
+	return &Media{ContentType: http.DetectContentType(data), Data: data}
+}
+
+// ParseDataURI parses a base64 data URI such as "data:image/png;base64,...".
+// It reports false for other strings, including data URIs that are not
+// base64-encoded.
+func ParseDataURI(s string) (*Media, bool) {
+	rest, ok := strings.CutPrefix(s, "data:")
+	if !ok {
+		return nil, false
+	}
+	header, encoded, ok := strings.Cut(rest, ",")
+	if !ok {
+		return nil, false
+	}
+	contentType, ok := strings.CutSuffix(header, ";base64")
+	if !ok {
+		return nil, false
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, false
+	}
+
+	return &Media{ContentType: contentType, Data: data}, true
+}
+
+// MediaType returns ContentType, or the type sniffed from Data when unset.
+func (m *Media) MediaType() string {
+	if m.ContentType != "" {
+		return m.ContentType
+	}
+	return http.DetectContentType(m.Data)
+}
+
+// DataURI returns the content as a base64 data URI.
+func (m *Media) DataURI() string {
+	return "data:" + m.MediaType() + ";base64," + base64.StdEncoding.EncodeToString(m.Data)
+}
+
+// MarshalJSON encodes the media as its data URI.
+func (m Media) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.DataURI())
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseDataURI(t *testing.T) {
+	m, ok := ParseDataURI("data:image/png;base64,aGVsbG8=")
+	if !ok || m.ContentType != "image/png" || string(m.Data) != "hello" {
+		t.Fatalf("media=%+v ok=%v", m, ok)
+	}
+	if m.DataURI() != "data:image/png;base64,aGVsbG8=" {
+		t.Errorf("DataURI()=%s", m.DataURI())
+	}
+
+	for _, s := range []string{"hello", "data:text/plain,hello", "data:image/png;base64,!!"} {
+		if _, ok := ParseDataURI(s); ok {
+			t.Errorf("%q must not parse", s)
+		}
+	}
+}
+
+func TestMedia_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(map[string]any{"audio": Media{Data: []byte("%PDF-1.7")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"audio":"data:application/pdf;base64,JVBERi0xLjc="}` {
+		t.Errorf("json=%s", data)
+	}
+}
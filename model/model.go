@@ -35,6 +35,9 @@ type Trace struct {
 	Metadata  any        `json:"metadata,omitempty"`
 	Tags      []string   `json:"tags,omitempty"`
 	Public    bool       `json:"public,omitempty"`
+	// Environment overrides the client environment for this trace and its
+	// observations.
+	Environment string `json:"environment,omitempty"`
 }
 
 type ObservationLevel string
@@ -82,6 +85,10 @@ type Generation struct {
 	PromptVersion       int              `json:"promptVersion,omitempty"`
 	// Prompt object reference for Linked Generations auto-matching.
 	Prompt *Prompt `json:"prompt,omitempty"`
+	// Environment defaults to the environment of the trace.
+	Environment string `json:"environment,omitempty"`
+	// Errors are exported as OTLP exception events; see RecordError.
+	Errors []ObservationError `json:"-"`
 }
 
 type Usage struct {
@@ -122,12 +129,21 @@ const (
 )
 
 type Score struct {
-	ID            string  `json:"id,omitempty"`
-	TraceID       string  `json:"traceId,omitempty"`
-	Name          string  `json:"name,omitempty"`
+	ID      string `json:"id,omitempty"`
+	TraceID string `json:"traceId,omitempty"`
+	Name    string `json:"name,omitempty"`
+	// Value holds NUMERIC scores and BOOLEAN scores as 0 or 1.
 	Value         float64 `json:"value,omitempty"`
 	ObservationID string  `json:"observationId,omitempty"`
 	Comment       string  `json:"comment,omitempty"`
+	// DataType defaults to the config's type when ConfigID is set, else to
+	// CATEGORICAL when StringValue is set and NUMERIC otherwise.
+	DataType ScoreDataType `json:"dataType,omitempty"`
+	// StringValue holds the category label of CATEGORICAL scores.
+	StringValue string `json:"stringValue,omitempty"`
+	// ConfigID links the score to a score config, which Langfuse uses to
+	// validate and display it.
+	ConfigID string `json:"configId,omitempty"`
 }
 
 type Span struct {
@@ -143,6 +159,10 @@ type Span struct {
 	Version             string           `json:"version,omitempty"`
 	ID                  string           `json:"id,omitempty"`
 	EndTime             *time.Time       `json:"endTime,omitempty"`
+	// Environment defaults to the environment of the trace.
+	Environment string `json:"environment,omitempty"`
+	// Errors are exported as OTLP exception events; see RecordError.
+	Errors []ObservationError `json:"-"`
 }
 
 type Event struct {
@@ -157,6 +177,10 @@ type Event struct {
 	ParentObservationID string           `json:"parentObservationId,omitempty"`
 	Version             string           `json:"version,omitempty"`
 	ID                  string           `json:"id,omitempty"`
+	// Environment defaults to the environment of the trace.
+	Environment string `json:"environment,omitempty"`
+	// Errors are exported as OTLP exception events; see RecordError.
+	Errors []ObservationError `json:"-"`
 }
 
 // ObservationView is decoded from `GET /api/public/v2/observations`.
@@ -206,6 +230,67 @@ type ObservationsCursorMeta struct {
 	Cursor *string `json:"cursor"`
 }
 
+// TraceView is decoded from `GET /api/public/traces` and
+// `GET /api/public/traces/{traceId}`.
+type TraceView struct {
+	ID          string     `json:"id"`
+	Timestamp   *time.Time `json:"timestamp,omitempty"`
+	Name        string     `json:"name,omitempty"`
+	UserID      string     `json:"userId,omitempty"`
+	SessionID   string     `json:"sessionId,omitempty"`
+	Input       any        `json:"input,omitempty"`
+	Output      any        `json:"output,omitempty"`
+	Metadata    any        `json:"metadata,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	Release     string     `json:"release,omitempty"`
+	Version     string     `json:"version,omitempty"`
+	Environment string     `json:"environment,omitempty"`
+	Public      bool       `json:"public,omitempty"`
+	Bookmarked  bool       `json:"bookmarked,omitempty"`
+	HTMLPath    string     `json:"htmlPath,omitempty"`
+	Latency     float64    `json:"latency,omitempty"`
+	TotalCost   float64    `json:"totalCost,omitempty"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt   *time.Time `json:"updatedAt,omitempty"`
+}
+
+// ScoreView is a score as returned by the public read APIs.
+type ScoreView struct {
+	ID            string        `json:"id"`
+	TraceID       string        `json:"traceId,omitempty"`
+	ObservationID string        `json:"observationId,omitempty"`
+	SessionID     string        `json:"sessionId,omitempty"`
+	Name          string        `json:"name,omitempty"`
+	Source        ScoreSource   `json:"source,omitempty"`
+	DataType      ScoreDataType `json:"dataType,omitempty"`
+	Value         float64       `json:"value,omitempty"`
+	StringValue   string        `json:"stringValue,omitempty"`
+	ConfigID      string        `json:"configId,omitempty"`
+	Comment       string        `json:"comment,omitempty"`
+	Environment   string        `json:"environment,omitempty"`
+	Timestamp     *time.Time    `json:"timestamp,omitempty"`
+	CreatedAt     *time.Time    `json:"createdAt,omitempty"`
+	UpdatedAt     *time.Time    `json:"updatedAt,omitempty"`
+}
+
+// SessionView is decoded from `GET /api/public/sessions` and
+// `GET /api/public/sessions/{sessionId}`.
+type SessionView struct {
+	ID          string     `json:"id"`
+	ProjectID   string     `json:"projectId,omitempty"`
+	Environment string     `json:"environment,omitempty"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+}
+
+// PageMeta is the pagination meta returned by page-based list endpoints such
+// as `GET /api/public/traces`.
+type PageMeta struct {
+	Page       int `json:"page"`
+	Limit      int `json:"limit"`
+	TotalItems int `json:"totalItems"`
+	TotalPages int `json:"totalPages"`
+}
+
 type Prompt struct {
 	ID          string     `json:"id,omitempty"`
 	Name        string     `json:"name,omitempty"`
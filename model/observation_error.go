@@ -0,0 +1,73 @@
+package model
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// ObservationError is a Go error recorded on an observation. It is exported
+// as an OTLP "exception" span event.
+type ObservationError struct {
+	// Type is the Go type of the error, e.g. "*fs.PathError".
+	Type    string
+	Message string
+	// Stacktrace is optional.
+	Stacktrace string
+	// Time defaults to the end (or start) of the observation.
+	Time *time.Time
+}
+
+// NewObservationError describes err, capturing the current goroutine stack
+// when withStack is set.
+func NewObservationError(err error, withStack bool) ObservationError {
+	now := time.Now().UTC()
+	e := ObservationError{
+		Type:    fmt.Sprintf("%T", err),
+		Message: err.Error(),
+		Time:    &now,
+	}
+	if withStack {
+		e.Stacktrace = string(debug.Stack())
+	}
+	return e
+}
+
+// RecordError records err on the generation, sets its level to ERROR and,
+// when empty, its status message to the error text. A nil err is ignored.
+func (g *Generation) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	g.Errors = append(g.Errors, NewObservationError(err, false))
+	g.Level = ObservationLevelError
+	if g.StatusMessage == "" {
+		g.StatusMessage = err.Error()
+	}
+}
+
+// RecordError records err on the span, sets its level to ERROR and, when
+// empty, its status message to the error text. A nil err is ignored.
+func (s *Span) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.Errors = append(s.Errors, NewObservationError(err, false))
+	s.Level = ObservationLevelError
+	if s.StatusMessage == "" {
+		s.StatusMessage = err.Error()
+	}
+}
+
+// RecordError records err on the event, sets its level to ERROR and, when
+// empty, its status message to the error text. A nil err is ignored.
+func (e *Event) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	e.Errors = append(e.Errors, NewObservationError(err, false))
+	e.Level = ObservationLevelError
+	if e.StatusMessage == "" {
+		e.StatusMessage = err.Error()
+	}
+}
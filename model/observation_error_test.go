@@ -0,0 +1,34 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRecordError(t *testing.T) {
+	s := &Span{StatusMessage: "kept"}
+	s.RecordError(nil)
+	if len(s.Errors) != 0 || s.Level != "" {
+		t.Fatalf("nil error must be ignored: %+v", s)
+	}
+
+	s.RecordError(errors.New("first"))
+	s.RecordError(errors.New("second"))
+	if len(s.Errors) != 2 || s.Level != ObservationLevelError || s.StatusMessage != "kept" {
+		t.Errorf("span=%+v", s)
+	}
+
+	g := &Generation{}
+	g.RecordError(errors.New("timeout"))
+	if g.StatusMessage != "timeout" || g.Errors[0].Type != "*errors.errorString" || g.Errors[0].Time == nil {
+		t.Errorf("generation=%+v", g)
+	}
+}
+
+func TestNewObservationError_Stack(t *testing.T) {
+	e := NewObservationError(errors.New("boom"), true)
+	if !strings.Contains(e.Stacktrace, "TestNewObservationError_Stack") {
+		t.Errorf("stacktrace does not include the caller:\n%s", e.Stacktrace)
+	}
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+// ObservationNode is an observation together with its child observations.
+type ObservationNode struct {
+	Observation *ObservationView
+	Children    []*ObservationNode
+}
+
+// Walk calls fn for n and every descendant in depth-first order. depth is 0
+// for n itself. Returning false skips the children of that node.
+func (n *ObservationNode) Walk(fn func(node *ObservationNode, depth int) bool) {
+	n.walk(fn, 0)
+}
+
+func (n *ObservationNode) walk(fn func(node *ObservationNode, depth int) bool, depth int) {
+	if !fn(n, depth) {
+		return
+	}
+	for _, child := range n.Children {
+		child.walk(fn, depth+1)
+	}
+}
+
+// BuildObservationTree links observations into a forest using
+// ParentObservationID and returns the roots. Observations whose parent is not
+// in the slice (or that reference themselves) become roots, so a partial
+// listing never drops data. Siblings are ordered by StartTime, then ID.
+func BuildObservationTree(observations []ObservationView) []*ObservationNode {
+	nodes := make(map[string]*ObservationNode, len(observations))
+	ordered := make([]*ObservationNode, 0, len(observations))
+	for i := range observations {
+		node := &ObservationNode{Observation: &observations[i]}
+		if _, dup := nodes[observations[i].ID]; !dup {
+			nodes[observations[i].ID] = node
+		}
+		ordered = append(ordered, node)
+	}
+
+	parents := make(map[*ObservationNode]*ObservationNode, len(ordered))
+	var roots []*ObservationNode
+	for _, node := range ordered {
+		parent, ok := nodes[node.Observation.ParentObservationID]
+		if !ok || createsCycle(parents, parent, node) {
+			roots = append(roots, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+		parents[node] = parent
+	}
+
+	sortObservationNodes(roots)
+	return roots
+}
+
+// createsCycle reports whether linking node under parent would close a loop,
+// i.e. node is parent itself or one of its ancestors.
+func createsCycle(parents map[*ObservationNode]*ObservationNode, parent, node *ObservationNode) bool {
+	for p := parent; p != nil; p = parents[p] {
+		if p == node {
+			return true
+		}
+	}
+	return false
+}
+
+func sortObservationNodes(nodes []*ObservationNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i].Observation, nodes[j].Observation
+		at, bt := startTimeOrZero(a), startTimeOrZero(b)
+		if !at.Equal(bt) {
+			return at.Before(bt)
+		}
+		return a.ID < b.ID
+	})
+	for _, node := range nodes {
+		sortObservationNodes(node.Children)
+	}
+}
+
+func startTimeOrZero(o *ObservationView) time.Time {
+	if o.StartTime == nil {
+		return time.Time{}
+	}
+	return *o.StartTime
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func treeString(roots []*ObservationNode) string {
+	var b strings.Builder
+	for _, root := range roots {
+		root.Walk(func(n *ObservationNode, depth int) bool {
+			b.WriteString(strings.Repeat("  ", depth) + n.Observation.ID + "\n")
+			return true
+		})
+	}
+	return b.String()
+}
+
+func TestBuildObservationTree(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(s int) *time.Time {
+		ts := base.Add(time.Duration(s) * time.Second)
+		return &ts
+	}
+
+	roots := BuildObservationTree([]ObservationView{
+		{ID: "llm-2", ParentObservationID: "agent", StartTime: at(3)},
+		{ID: "tool", ParentObservationID: "llm-1", StartTime: at(2)},
+		{ID: "agent", StartTime: at(0)},
+		{ID: "llm-1", ParentObservationID: "agent", StartTime: at(1)},
+		{ID: "late-root", StartTime: at(5)},
+	})
+
+	want := "agent\n  llm-1\n    tool\n  llm-2\nlate-root\n"
+	if got := treeString(roots); got != want {
+		t.Errorf("tree=\n%s\nwant\n%s", got, want)
+	}
+}
+
+// TestBuildObservationTree_MissingParent verifies observations whose parent
+// is absent are kept as roots.
+func TestBuildObservationTree_MissingParent(t *testing.T) {
+	roots := BuildObservationTree([]ObservationView{
+		{ID: "a", ParentObservationID: "gone"},
+		{ID: "b", ParentObservationID: "a"},
+	})
+	if got := treeString(roots); got != "a\n  b\n" {
+		t.Errorf("tree=\n%s", got)
+	}
+}
+
+func TestBuildObservationTree_Cycle(t *testing.T) {
+	roots := BuildObservationTree([]ObservationView{
+		{ID: "a", ParentObservationID: "b"},
+		{ID: "b", ParentObservationID: "a"},
+		{ID: "self", ParentObservationID: "self"},
+	})
+	if got := treeString(roots); got != "b\n  a\nself\n" {
+		t.Errorf("tree=\n%s", got)
+	}
+}
+
+func TestObservationNode_WalkSkipsChildren(t *testing.T) {
+	roots := BuildObservationTree([]ObservationView{
+		{ID: "root"},
+		{ID: "child", ParentObservationID: "root"},
+		{ID: "grandchild", ParentObservationID: "child"},
+	})
+
+	var seen []string
+	roots[0].Walk(func(n *ObservationNode, _ int) bool {
+		seen = append(seen, n.Observation.ID)
+		return n.Observation.ID != "child"
+	})
+	if got := strings.Join(seen, ","); got != "root,child" {
+		t.Errorf("walked %s, want root,child", got)
+	}
+}
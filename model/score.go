@@ -0,0 +1,117 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+type ScoreDataType string
+
+const (
+	ScoreDataTypeNumeric     ScoreDataType = "NUMERIC"
+	ScoreDataTypeBoolean     ScoreDataType = "BOOLEAN"
+	ScoreDataTypeCategorical ScoreDataType = "CATEGORICAL"
+)
+
+type ScoreSource string
+
+const (
+	ScoreSourceAPI        ScoreSource = "API"
+	ScoreSourceEval       ScoreSource = "EVAL"
+	ScoreSourceAnnotation ScoreSource = "ANNOTATION"
+)
+
+// ScoreConfig defines the data type and allowed values of a score. It is
+// decoded from `GET /api/public/score-configs`.
+type ScoreConfig struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	DataType    ScoreDataType `json:"dataType"`
+	IsArchived  bool          `json:"isArchived,omitempty"`
+	Description string        `json:"description,omitempty"`
+	// MinValue and MaxValue bound NUMERIC scores; nil means unbounded.
+	MinValue *float64 `json:"minValue,omitempty"`
+	MaxValue *float64 `json:"maxValue,omitempty"`
+	// Categories lists the allowed labels of CATEGORICAL scores.
+	Categories []ScoreConfigCategory `json:"categories,omitempty"`
+	CreatedAt  *time.Time            `json:"createdAt,omitempty"`
+	UpdatedAt  *time.Time            `json:"updatedAt,omitempty"`
+}
+
+type ScoreConfigCategory struct {
+	Label string  `json:"label"`
+	Value float64 `json:"value"`
+}
+
+// EffectiveDataType returns the data type the score is sent with: DataType
+// when set, otherwise CATEGORICAL when StringValue is set and NUMERIC when
+// it is not.
+func (s *Score) EffectiveDataType() ScoreDataType {
+	switch {
+	case s.DataType != "":
+		return s.DataType
+	case s.StringValue != "":
+		return ScoreDataTypeCategorical
+	default:
+		return ScoreDataTypeNumeric
+	}
+}
+
+// Validate checks that the score value fits its data type.
+func (s *Score) Validate() error {
+	switch s.EffectiveDataType() {
+	case ScoreDataTypeNumeric:
+		if math.IsNaN(s.Value) || math.IsInf(s.Value, 0) {
+			return fmt.Errorf("score %q: value must be a finite number", s.Name)
+		}
+	case ScoreDataTypeBoolean:
+		if s.Value != 0 && s.Value != 1 {
+			return fmt.Errorf("score %q: boolean value must be 0 or 1, got %v", s.Name, s.Value)
+		}
+	case ScoreDataTypeCategorical:
+		if s.StringValue == "" {
+			return fmt.Errorf("score %q: categorical score requires StringValue", s.Name)
+		}
+	default:
+		return fmt.Errorf("score %q: unknown data type %q", s.Name, s.DataType)
+	}
+
+	return nil
+}
+
+// Validate checks that score is acceptable under the config: the config is
+// not archived, the data types agree, and the value lies within the range or
+// categories the config allows. A score without DataType takes the config's.
+func (c *ScoreConfig) Validate(score *Score) error {
+	if c.IsArchived {
+		return fmt.Errorf("score config %q is archived", c.Name)
+	}
+
+	if score.DataType != "" && score.DataType != c.DataType {
+		return fmt.Errorf("score %q: data type %s does not match config %q (%s)", score.Name, score.DataType, c.Name, c.DataType)
+	}
+
+	typed := *score
+	typed.DataType = c.DataType
+	if err := typed.Validate(); err != nil {
+		return err
+	}
+
+	switch c.DataType {
+	case ScoreDataTypeNumeric:
+		if c.MinValue != nil && score.Value < *c.MinValue {
+			return fmt.Errorf("score %q: value %v is below the minimum %v of config %q", score.Name, score.Value, *c.MinValue, c.Name)
+		}
+		if c.MaxValue != nil && score.Value > *c.MaxValue {
+			return fmt.Errorf("score %q: value %v is above the maximum %v of config %q", score.Name, score.Value, *c.MaxValue, c.Name)
+		}
+	case ScoreDataTypeCategorical:
+		if !slices.ContainsFunc(c.Categories, func(cat ScoreConfigCategory) bool { return cat.Label == score.StringValue }) {
+			return fmt.Errorf("score %q: %q is not a category of config %q", score.Name, score.StringValue, c.Name)
+		}
+	}
+
+	return nil
+}
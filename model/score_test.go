@@ -0,0 +1,80 @@
+package model
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestScore_Validate(t *testing.T) {
+	valid := []Score{
+		{Name: "n", Value: 0.5},
+		{Name: "n", Value: -3, DataType: ScoreDataTypeNumeric},
+		{Name: "b", Value: 0, DataType: ScoreDataTypeBoolean},
+		{Name: "b", Value: 1, DataType: ScoreDataTypeBoolean},
+		{Name: "c", StringValue: "good", DataType: ScoreDataTypeCategorical},
+	}
+	for _, s := range valid {
+		if err := s.Validate(); err != nil {
+			t.Errorf("%+v: unexpected error: %v", s, err)
+		}
+	}
+
+	invalid := []Score{
+		{Name: "n", Value: math.NaN()},
+		{Name: "b", Value: 0.5, DataType: ScoreDataTypeBoolean},
+		{Name: "c", Value: 1, DataType: ScoreDataTypeCategorical},
+		{Name: "x", DataType: "PERCENT"},
+	}
+	for _, s := range invalid {
+		if err := s.Validate(); err == nil {
+			t.Errorf("%+v: expected error", s)
+		}
+	}
+}
+
+func TestScoreConfig_Validate_NumericRange(t *testing.T) {
+	minValue, maxValue := 0.0, 10.0
+	config := &ScoreConfig{Name: "rating", DataType: ScoreDataTypeNumeric, MinValue: &minValue, MaxValue: &maxValue}
+
+	if err := config.Validate(&Score{Name: "rating", Value: 10}); err != nil {
+		t.Errorf("upper bound must be inclusive: %v", err)
+	}
+	if err := config.Validate(&Score{Name: "rating", Value: 11}); err == nil || !strings.Contains(err.Error(), "maximum") {
+		t.Errorf("err=%v, want maximum error", err)
+	}
+	if err := config.Validate(&Score{Name: "rating", Value: -1}); err == nil || !strings.Contains(err.Error(), "minimum") {
+		t.Errorf("err=%v, want minimum error", err)
+	}
+}
+
+func TestScoreConfig_Validate_Categories(t *testing.T) {
+	config := &ScoreConfig{
+		Name:       "tone",
+		DataType:   ScoreDataTypeCategorical,
+		Categories: []ScoreConfigCategory{{Label: "polite", Value: 1}, {Label: "rude", Value: 0}},
+	}
+
+	if err := config.Validate(&Score{Name: "tone", StringValue: "polite"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := config.Validate(&Score{Name: "tone", StringValue: "neutral"}); err == nil {
+		t.Error("expected error for unknown category")
+	}
+}
+
+func TestScoreConfig_Validate_TypeMismatchAndArchived(t *testing.T) {
+	config := &ScoreConfig{Name: "ok", DataType: ScoreDataTypeBoolean}
+
+	if err := config.Validate(&Score{Name: "ok", Value: 1, DataType: ScoreDataTypeNumeric}); err == nil {
+		t.Error("expected data type mismatch error")
+	}
+	if err := config.Validate(&Score{Name: "ok", Value: 2}); err == nil {
+		t.Error("expected boolean value error under a boolean config")
+	}
+
+	config.IsArchived = true
+	if err := config.Validate(&Score{Name: "ok", Value: 1}); err == nil {
+		t.Error("expected archived config error")
+	}
+}
@@ -0,0 +1,153 @@
+package langfuse
+
+import (
+	"context"
+	"iter"
+	"strings"
+	"time"
+
+	"github.com/ezardev-team/langfuse-go/internal/pkg/api"
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+// Observation field groups for ObservationQuery.Fields. The core group is
+// always returned.
+const (
+	ObservationFieldsCore     = "core"
+	ObservationFieldsBasic    = "basic"
+	ObservationFieldsTime     = "time"
+	ObservationFieldsIO       = "io"
+	ObservationFieldsMetadata = "metadata"
+	ObservationFieldsModel    = "model"
+	ObservationFieldsUsage    = "usage"
+	ObservationFieldsPrompt   = "prompt"
+	ObservationFieldsMetrics  = "metrics"
+)
+
+// ObservationFieldsAll selects every field group. It is the default for
+// ObservationQuery.Fields.
+var ObservationFieldsAll = []string{
+	ObservationFieldsBasic,
+	ObservationFieldsTime,
+	ObservationFieldsIO,
+	ObservationFieldsMetadata,
+	ObservationFieldsModel,
+	ObservationFieldsUsage,
+	ObservationFieldsPrompt,
+	ObservationFieldsMetrics,
+}
+
+// ObservationQuery selects observations for ListObservations.
+type ObservationQuery struct {
+	Name                string
+	UserID              string
+	Type                model.ObservationType
+	TraceID             string
+	Level               model.ObservationLevel
+	ParentObservationID string
+	// Environment defaults to the client environment set WithEnvironment.
+	Environment   []string
+	FromStartTime *time.Time
+	ToStartTime   *time.Time
+	Version       string
+	// Filters narrows the result with typed conditions, e.g.
+	// ObservationColumnMetadata.Key("tenant").Eq("acme"). Conditions are
+	// validated before the first request is sent.
+	Filters []FilterCondition
+	// ExpandMetadata lists metadata keys whose values should be returned in
+	// full instead of truncated.
+	ExpandMetadata []string
+	PageSize       int
+	// Fields lists the field groups to return. Defaults to
+	// ObservationFieldsAll.
+	Fields []string
+}
+
+func (q *ObservationQuery) request() (api.ObservationsRequest, error) {
+	filter, err := encodeFilter(q.Filters)
+	if err != nil {
+		return api.ObservationsRequest{}, err
+	}
+
+	req := api.ObservationsRequest{
+		Fields:              api.ObservationFieldsAll,
+		ExpandMetadata:      strings.Join(q.ExpandMetadata, ","),
+		Name:                q.Name,
+		UserID:              q.UserID,
+		Type:                q.Type,
+		TraceID:             q.TraceID,
+		Level:               q.Level,
+		ParentObservationID: q.ParentObservationID,
+		Environment:         q.Environment,
+		FromStartTime:       q.FromStartTime,
+		ToStartTime:         q.ToStartTime,
+		Version:             q.Version,
+		Filter:              filter,
+	}
+
+	if len(q.Fields) > 0 {
+		req.Fields = strings.Join(q.Fields, ",")
+	}
+
+	if q.PageSize > 0 {
+		limit := q.PageSize
+		req.Limit = &limit
+	}
+
+	return req, nil
+}
+
+// ListObservations returns an iterator over the observations matching query.
+//
+//	for obs, err := range l.ListObservations(ctx, langfuse.ObservationQuery{TraceID: id}) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(obs.Name)
+//	}
+func (l *Langfuse) ListObservations(ctx context.Context, query ObservationQuery) iter.Seq2[*model.ObservationView, error] {
+	return func(yield func(*model.ObservationView, error) bool) {
+		if len(query.Environment) == 0 && l.environment() != "" {
+			query.Environment = []string{l.environment()}
+		}
+
+		req, err := query.request()
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			res := api.ObservationsResponse{}
+			if err := l.client.Observations(ctx, &req, &res); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if !res.IsSuccess() {
+				yield(nil, observationsStatusError(&res))
+				return
+			}
+
+			for i := range res.Data {
+				if !yield(&res.Data[i], nil) {
+					return
+				}
+			}
+
+			if len(res.Data) == 0 || res.Meta.Cursor == nil || *res.Meta.Cursor == "" {
+				return
+			}
+			req.Cursor = *res.Meta.Cursor
+		}
+	}
+}
+
+func observationsStatusError(res *api.ObservationsResponse) error {
+	return newAPIError("observations", &api.ObservationsRequest{}, res.Code, res.RawBody)
+}
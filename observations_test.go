@@ -0,0 +1,198 @@
+package langfuse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+// pagedObservationsServer serves pages of observations keyed by cursor and
+// records every query it receives.
+func pagedObservationsServer(t *testing.T, pages map[string][]string) (*httptest.Server, *[]url.Values) {
+	t.Helper()
+	var mu sync.Mutex
+	var queries []url.Values
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.Query())
+		mu.Unlock()
+
+		cursor := r.URL.Query().Get("cursor")
+		ids, ok := pages[cursor]
+		if !ok {
+			t.Errorf("unexpected cursor %q", cursor)
+		}
+
+		data := make([]map[string]any, 0, len(ids))
+		for _, id := range ids {
+			data = append(data, map[string]any{"id": id, "type": "SPAN"})
+		}
+
+		var next any
+		if _, ok := pages[cursor+"+"]; ok {
+			next = cursor + "+"
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": data,
+			"meta": map[string]any{"cursor": next},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &queries
+}
+
+func TestListObservations_FollowsCursor(t *testing.T) {
+	srv, queries := pagedObservationsServer(t, map[string][]string{
+		"":   {"a", "b"},
+		"+":  {"c"},
+		"++": {"d"},
+	})
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	var ids []string
+	for obs, err := range l.ListObservations(context.Background(), ObservationQuery{
+		TraceID:  "trace-1",
+		Type:     model.ObservationTypeSpan,
+		PageSize: 2,
+	}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, obs.ID)
+	}
+
+	if got := strings.Join(ids, ","); got != "a,b,c,d" {
+		t.Errorf("ids=%s, want a,b,c,d", got)
+	}
+	if len(*queries) != 3 {
+		t.Fatalf("requests=%d, want 3", len(*queries))
+	}
+
+	first := (*queries)[0]
+	if first.Get("traceId") != "trace-1" || first.Get("type") != "SPAN" || first.Get("limit") != "2" {
+		t.Errorf("first query=%v", first)
+	}
+	for _, group := range ObservationFieldsAll {
+		if !strings.Contains(first.Get("fields"), group) {
+			t.Errorf("fields=%q must default to include %q", first.Get("fields"), group)
+		}
+	}
+	if got := (*queries)[2].Get("cursor"); got != "++" {
+		t.Errorf("third cursor=%q, want ++", got)
+	}
+}
+
+func TestListObservations_CustomFields(t *testing.T) {
+	srv, queries := pagedObservationsServer(t, map[string][]string{"": {"a"}})
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	for _, err := range l.ListObservations(context.Background(), ObservationQuery{
+		Fields: []string{ObservationFieldsBasic, ObservationFieldsUsage},
+	}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if got := (*queries)[0].Get("fields"); got != "basic,usage" {
+		t.Errorf("fields=%q, want basic,usage", got)
+	}
+}
+
+func TestListObservations_StopsWhenCallerBreaks(t *testing.T) {
+	srv, queries := pagedObservationsServer(t, map[string][]string{
+		"":  {"a", "b"},
+		"+": {"c"},
+	})
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	for obs, err := range l.ListObservations(context.Background(), ObservationQuery{}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if obs.ID == "a" {
+			break
+		}
+	}
+
+	if len(*queries) != 1 {
+		t.Errorf("requests=%d, want 1", len(*queries))
+	}
+}
+
+func TestListObservations_ContextCancelled(t *testing.T) {
+	srv, queries := pagedObservationsServer(t, map[string][]string{
+		"":  {"a"},
+		"+": {"b"},
+	})
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var gotErr error
+	for obs, err := range l.ListObservations(ctx, ObservationQuery{}) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+		if obs.ID == "a" {
+			cancel()
+		}
+	}
+
+	if !errors.Is(gotErr, context.Canceled) {
+		t.Errorf("err=%v, want context.Canceled", gotErr)
+	}
+	if len(*queries) != 1 {
+		t.Errorf("requests=%d, want 1", len(*queries))
+	}
+}
+
+func TestListObservations_StatusError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message":"bad key"}`))
+	}))
+	defer srv.Close()
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	count := 0
+	for obs, err := range l.ListObservations(context.Background(), ObservationQuery{}) {
+		count++
+		if err == nil || obs != nil {
+			t.Fatalf("obs=%v err=%v, want a single error", obs, err)
+		}
+		if !strings.Contains(err.Error(), fmt.Sprint(http.StatusUnauthorized)) {
+			t.Errorf("err=%v, want status code in message", err)
+		}
+	}
+	if count != 1 {
+		t.Errorf("yielded %d times, want 1", count)
+	}
+}
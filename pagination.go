@@ -0,0 +1,47 @@
+package langfuse
+
+import (
+	"context"
+	"iter"
+
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+// fetchPage requests one page (starting at 1) of a page-based list endpoint.
+type fetchPage[T any] func(ctx context.Context, page int) ([]T, model.PageMeta, error)
+
+// listPages turns a page-based list endpoint into an iterator. It stops after
+// the last page reported by the API or at the first empty page.
+//
+// All List methods (ListTraces, ListSessions, ListScores, ListDatasetItems and
+// the cursor-based ListObservations) behave alike: pages are requested as the
+// iterator is consumed, iteration stops at the first error, which is yielded
+// with a nil item, and context cancellation is checked before every page.
+// Their query types leave zero-valued fields out of the request, and a zero
+// PageSize uses the API default.
+func listPages[T any](ctx context.Context, fetch fetchPage[T]) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		for page := 1; ; page++ {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			data, meta, err := fetch(ctx, page)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for i := range data {
+				if !yield(&data[i], nil) {
+					return
+				}
+			}
+
+			if len(data) == 0 || page >= meta.TotalPages {
+				return
+			}
+		}
+	}
+}
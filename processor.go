@@ -0,0 +1,137 @@
+package langfuse
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+// EventProcessor inspects, changes or drops an event before it is queued for
+// export. It returns false to drop the event. event.Body points to the
+// struct passed to Trace, Span and the like; replace it with a copy to
+// change the export without changing the caller's value.
+type EventProcessor interface {
+	Process(ctx context.Context, event *model.IngestionEvent) (keep bool)
+}
+
+// EventProcessorFunc adapts a function to an EventProcessor.
+type EventProcessorFunc func(ctx context.Context, event *model.IngestionEvent) (keep bool)
+
+func (f EventProcessorFunc) Process(ctx context.Context, event *model.IngestionEvent) bool {
+	return f(ctx, event)
+}
+
+// WithEventProcessor appends processors to the chain run on every trace,
+// observation and score event, in registration order, after sampling and
+// before the event is queued. Dropping a trace-create event also drops the
+// events of the trace dispatched after it. A processor that panics does not
+// stop the chain: the panic is passed to the export error handler and the
+// event continues to the next processor with its fields, and the top-level
+// fields of its body, as the processor received them. Maps and slices the
+// processor changed in place are not restored.
+//
+// A Sampler set with WithSampler runs before every processor; a MaskFunc set
+// with WithMask runs at export, on copies of the payloads and before media
+// uploads. To run them at a given point of the chain instead, register
+// SamplerProcessor or MaskProcessor.
+func (l *Langfuse) WithEventProcessor(processors ...EventProcessor) *Langfuse {
+	l.processors = append(l.processors, processors...)
+	return l
+}
+
+// dispatch queues event unless its trace is not sampled or an event
+// processor drops it or its trace.
+func (l *Langfuse) dispatch(event model.IngestionEvent) {
+	if !l.sampleEvent(event) {
+		l.exportStats.update(func(s *ExportStats) { s.Unsampled++ })
+		return
+	}
+	traceID := eventTraceID(event)
+	if _, dropped := l.droppedTraces.get(traceID); dropped {
+		l.exportStats.update(func(s *ExportStats) { s.Filtered++ })
+		return
+	}
+	if !l.processEvent(&event) {
+		if event.Type == model.IngestionEventTypeTraceCreate && traceID != "" {
+			l.droppedTraces.set(traceID, struct{}{})
+		}
+		l.exportStats.update(func(s *ExportStats) { s.Filtered++ })
+		return
+	}
+	l.stampEnvironment(event)
+	l.observer.Dispatch(event)
+}
+
+// processEvent runs the processor chain on event and reports whether it is
+// kept.
+func (l *Langfuse) processEvent(event *model.IngestionEvent) bool {
+	ctx := l.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	for i, processor := range l.processors {
+		if !l.runProcessor(ctx, i, processor, event) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (l *Langfuse) runProcessor(ctx context.Context, i int, processor EventProcessor, event *model.IngestionEvent) (keep bool) {
+	before := *event
+	restoreBody := snapshotBody(event.Body)
+	defer func() {
+		if r := recover(); r != nil {
+			restoreBody()
+			*event = before
+			l.handleExportError(fmt.Errorf("event processor %d: panic: %v", i, r))
+			keep = true
+		}
+	}()
+
+	return processor.Process(ctx, event)
+}
+
+// snapshotBody copies the top-level fields of an event body and returns a
+// function writing them back.
+func snapshotBody(body any) (restore func()) {
+	switch b := body.(type) {
+	case *model.Trace:
+		saved := *b
+		return func() { *b = saved }
+	case *model.Generation:
+		saved := *b
+		return func() { *b = saved }
+	case *model.Span:
+		saved := *b
+		return func() { *b = saved }
+	case *model.Event:
+		saved := *b
+		return func() { *b = saved }
+	case *model.Score:
+		saved := *b
+		return func() { *b = saved }
+	}
+	return func() {}
+}
+
+// eventTraceID returns the ID of the trace event belongs to, or "" when it
+// names none.
+func eventTraceID(event model.IngestionEvent) string {
+	switch body := event.Body.(type) {
+	case *model.Trace:
+		return body.ID
+	case *model.Generation:
+		return body.TraceID
+	case *model.Span:
+		return body.TraceID
+	case *model.Event:
+		return body.TraceID
+	case *model.Score:
+		return body.TraceID
+	}
+	return ""
+}
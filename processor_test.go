@@ -0,0 +1,236 @@
+package langfuse
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ezardev-team/langfuse-go/model"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestProcessEvent_Order(t *testing.T) {
+	var seen []string
+	l := (&Langfuse{}).WithEventProcessor(
+		EventProcessorFunc(func(_ context.Context, event *model.IngestionEvent) bool {
+			event.Body.(*model.Span).Name += "-a"
+			return true
+		}),
+		EventProcessorFunc(func(_ context.Context, event *model.IngestionEvent) bool {
+			seen = append(seen, event.Body.(*model.Span).Name)
+			return event.Body.(*model.Span).Name != "drop-a"
+		}),
+		EventProcessorFunc(func(_ context.Context, event *model.IngestionEvent) bool {
+			seen = append(seen, "last:"+event.Body.(*model.Span).Name)
+			return true
+		}),
+	)
+
+	if !l.processEvent(&model.IngestionEvent{Body: &model.Span{Name: "keep"}}) {
+		t.Error("keep must be kept")
+	}
+	if l.processEvent(&model.IngestionEvent{Body: &model.Span{Name: "drop"}}) {
+		t.Error("drop must be dropped")
+	}
+	if got := strings.Join(seen, ","); got != "keep-a,last:keep-a,drop-a" {
+		t.Errorf("seen=%s", got)
+	}
+}
+
+func TestWithEventProcessor(t *testing.T) {
+	var (
+		l          *Langfuse
+		generation *model.Generation
+		errs       []error
+	)
+	body, _ := captureOTLP(t, func(lf *Langfuse) {
+		l = lf.WithExportErrorHandler(func(err error) { errs = append(errs, err) })
+		l.WithEventProcessor(
+			EventProcessorFunc(func(_ context.Context, event *model.IngestionEvent) bool {
+				trace, ok := event.Body.(*model.Trace)
+				return !ok || trace.Name != "health-check"
+			}),
+			EventProcessorFunc(func(_ context.Context, event *model.IngestionEvent) bool {
+				panic("broken processor")
+			}),
+			EventProcessorFunc(func(_ context.Context, event *model.IngestionEvent) bool {
+				if trace, ok := event.Body.(*model.Trace); ok {
+					enriched := *trace
+					enriched.Release = "v1.2.3"
+					event.Body = &enriched
+				}
+				return true
+			}),
+			EventProcessorFunc(func(_ context.Context, event *model.IngestionEvent) bool {
+				if g, ok := event.Body.(*model.Generation); ok && g.Model == "gpt-4o-2024-08-06" {
+					renamed := *g
+					renamed.Model = "gpt-4o"
+					event.Body = &renamed
+				}
+				return true
+			}),
+		)
+
+		_, _ = l.Trace(&model.Trace{Name: "health-check"})
+		trace, _ := l.Trace(&model.Trace{Name: "request"})
+		generation, _ = l.Generation(&model.Generation{TraceID: trace.ID, Name: "chat", Model: "gpt-4o-2024-08-06"}, nil)
+	})
+
+	var req coltracepb.ExportTraceServiceRequest
+	if err := proto.Unmarshal(body, &req); err != nil {
+		t.Fatal(err)
+	}
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	chat := findObservationSpan(spans, "chat", "generation")
+	if chat == nil {
+		t.Fatal("generation span not exported")
+	}
+	if got, _ := spanAttr(chat, "langfuse.observation.model.name"); got != "gpt-4o" {
+		t.Errorf("model=%q, want renamed", got)
+	}
+	if got, _ := spanAttr(chat, "langfuse.release"); got != "v1.2.3" {
+		t.Errorf("release=%q, want enriched", got)
+	}
+	if generation.Model != "gpt-4o-2024-08-06" {
+		t.Error("caller's generation was modified")
+	}
+	for _, span := range spans {
+		if name, _ := spanAttr(span, "langfuse.trace.name"); name == "health-check" {
+			t.Error("health-check trace was exported")
+		}
+	}
+
+	// The panicking processor runs once per event; the chain goes on.
+	if len(errs) != 2 || !strings.Contains(errs[0].Error(), "event processor 1: panic: broken processor") {
+		t.Errorf("errs=%v", errs)
+	}
+	if stats := l.Stats(); stats.Filtered != 1 {
+		t.Errorf("stats=%+v", stats)
+	}
+}
+
+func TestWithEventProcessor_DroppedTraceDropsLaterEvents(t *testing.T) {
+	var l *Langfuse
+	body, _ := captureOTLP(t, func(lf *Langfuse) {
+		l = lf.WithEventProcessor(EventProcessorFunc(func(_ context.Context, event *model.IngestionEvent) bool {
+			trace, ok := event.Body.(*model.Trace)
+			return !ok || trace.Name != "health-check"
+		}))
+
+		dropped, _ := l.Trace(&model.Trace{Name: "health-check"})
+		_, _ = l.Span(&model.Span{TraceID: dropped.ID, Name: "dropped-span"}, nil)
+		_, _ = l.Score(&model.Score{TraceID: dropped.ID, Name: "ok", Value: 1})
+
+		kept, _ := l.Trace(&model.Trace{Name: "request"})
+		_, _ = l.Span(&model.Span{TraceID: kept.ID, Name: "kept-span"}, nil)
+	})
+
+	var req coltracepb.ExportTraceServiceRequest
+	if err := proto.Unmarshal(body, &req); err != nil {
+		t.Fatal(err)
+	}
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	if findObservationSpan(spans, "dropped-span", "span") != nil {
+		t.Error("span of a dropped trace was exported")
+	}
+	if findObservationSpan(spans, "kept-span", "span") == nil {
+		t.Error("span of a kept trace was not exported")
+	}
+	if stats := l.Stats(); stats.Filtered != 3 || stats.Scores != 0 {
+		t.Errorf("stats=%+v, want the trace, span and score filtered", stats)
+	}
+}
+
+func TestProcessEvent_PanicRestoresBody(t *testing.T) {
+	var seen string
+	l := (&Langfuse{}).WithExportErrorHandler(func(error) {}).WithEventProcessor(
+		EventProcessorFunc(func(_ context.Context, event *model.IngestionEvent) bool {
+			event.Body.(*model.Span).Name = "half-written"
+			panic("boom")
+		}),
+		EventProcessorFunc(func(_ context.Context, event *model.IngestionEvent) bool {
+			seen = event.Body.(*model.Span).Name
+			return true
+		}),
+	)
+
+	span := &model.Span{Name: "original"}
+	if !l.processEvent(&model.IngestionEvent{Body: span}) {
+		t.Fatal("event must be kept")
+	}
+	if seen != "original" || span.Name != "original" {
+		t.Errorf("next processor saw %q, span name %q, want original", seen, span.Name)
+	}
+}
+
+func TestSamplerProcessor(t *testing.T) {
+	var calls int
+	l := (&Langfuse{}).WithEventProcessor(
+		EventProcessorFunc(func(_ context.Context, event *model.IngestionEvent) bool {
+			if trace, ok := event.Body.(*model.Trace); ok && trace.ID == "t-1" {
+				tagged := *trace
+				tagged.Tags = []string{"health-check"}
+				event.Body = &tagged
+			}
+			return true
+		}),
+		SamplerProcessor(SamplerFunc(func(trace *model.Trace) bool {
+			calls++
+			return !slices.Contains(trace.Tags, "health-check")
+		})),
+	)
+
+	if l.processEvent(&model.IngestionEvent{Body: &model.Trace{ID: "t-1"}}) {
+		t.Error("the trace tagged by the earlier processor must be dropped")
+	}
+	if l.processEvent(&model.IngestionEvent{Body: &model.Span{ID: "s-1", TraceID: "t-1"}}) {
+		t.Error("the span of the dropped trace must be dropped")
+	}
+	if !l.processEvent(&model.IngestionEvent{Body: &model.Span{ID: "s-2", TraceID: "t-2"}}) {
+		t.Error("the span of another trace must be kept")
+	}
+	if !l.processEvent(&model.IngestionEvent{Body: &model.Span{ID: "s-3"}}) {
+		t.Error("a span without trace must be kept")
+	}
+	if calls != 2 {
+		t.Errorf("sampler calls=%d, want one per trace", calls)
+	}
+	if !l.IsSampled("t-1") {
+		t.Error("IsSampled must not consult the processor's decisions")
+	}
+}
+
+func TestMaskProcessor(t *testing.T) {
+	var errs []error
+	var seen *model.Span
+	l := (&Langfuse{}).WithEventProcessor(
+		MaskProcessor(func(field MaskField, value any) (any, error) {
+			if field == MaskFieldOutput {
+				return nil, errors.New("bad rule")
+			}
+			return NewMask(nil, MaskEmails(MaskReplace))(field, value)
+		}, func(err error) { errs = append(errs, err) }),
+		EventProcessorFunc(func(_ context.Context, event *model.IngestionEvent) bool {
+			seen = event.Body.(*model.Span)
+			return true
+		}),
+	)
+
+	span := &model.Span{ID: "s-1", TraceID: "t-1", Input: "mail jane@example.com", Output: "jane@example.com", StatusMessage: "jane@example.com bounced"}
+	if !l.processEvent(&model.IngestionEvent{Body: span}) {
+		t.Fatal("the span must be kept")
+	}
+
+	if seen.Input != "mail "+MaskRedacted || seen.StatusMessage != MaskRedacted+" bounced" {
+		t.Errorf("input=%v status=%q, want masked for later processors", seen.Input, seen.StatusMessage)
+	}
+	if seen.Output != MaskFailed || len(errs) != 1 {
+		t.Errorf("output=%v errs=%v, want %q and the error", seen.Output, errs, MaskFailed)
+	}
+	if span.Input != "mail jane@example.com" {
+		t.Error("the caller's span must not be modified")
+	}
+}
@@ -64,12 +64,9 @@ func (l *Langfuse) UpsertPrompt(ctx context.Context, req UpsertPromptRequest) (*
 	}
 
 	if !res.IsSuccess() {
-		if res.RawBody != nil {
-			log.Printf("UpsertPrompt failed with status code: %d (path=%s name=%s) body=%s", res.Code, path, req.Name, *res.RawBody)
-			return nil, fmt.Errorf("UpsertPrompt %q failed: status=%d body=%s", req.Name, res.Code, *res.RawBody)
-		}
-		log.Printf("UpsertPrompt failed with status code: %d (path=%s name=%s)", res.Code, path, req.Name)
-		return nil, fmt.Errorf("UpsertPrompt %q failed: status=%d", req.Name, res.Code)
+		apiErr := newAPIError("UpsertPrompt", apiReq, res.Code, res.RawBody)
+		log.Printf("%v (name=%s)", apiErr, req.Name)
+		return nil, fmt.Errorf("UpsertPrompt %q: %w", req.Name, apiErr)
 	}
 
 	return &res.Prompt, nil
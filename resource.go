@@ -0,0 +1,68 @@
+package langfuse
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/ezardev-team/langfuse-go/internal/pkg/otel"
+)
+
+// Resource identifies the service emitting traces. It is sent as OTLP
+// resource attributes with every export, so traces from different services
+// can be told apart in Langfuse.
+type Resource struct {
+	// ServiceName is reported as service.name and defaults to "langfuse-go".
+	ServiceName string
+	// ServiceVersion is reported as service.version.
+	ServiceVersion string
+	// DeploymentEnvironment is reported as deployment.environment.name.
+	DeploymentEnvironment string
+	// Host adds host.name and host.arch.
+	Host bool
+	// Process adds process.pid, process.executable.name,
+	// process.runtime.name and process.runtime.version.
+	Process bool
+	// Attributes are added as-is and override the attributes above. Values
+	// may be strings, bools, integers, floats or []string; other values are
+	// JSON-encoded.
+	Attributes map[string]any
+}
+
+// WithResource sets the resource attributes of trace exports. Host and
+// process attributes are detected once, when WithResource is called.
+func (l *Langfuse) WithResource(r Resource) *Langfuse {
+	l.encoder.Resource = r.otel()
+	return l
+}
+
+func (r Resource) otel() otel.Resource {
+	attrs := map[string]any{}
+
+	if r.Host {
+		if hostname, err := os.Hostname(); err == nil {
+			attrs["host.name"] = hostname
+		}
+		attrs["host.arch"] = runtime.GOARCH
+	}
+
+	if r.Process {
+		attrs["process.pid"] = os.Getpid()
+		if executable, err := os.Executable(); err == nil {
+			attrs["process.executable.name"] = filepath.Base(executable)
+		}
+		attrs["process.runtime.name"] = "go"
+		attrs["process.runtime.version"] = runtime.Version()
+	}
+
+	for key, value := range r.Attributes {
+		attrs[key] = value
+	}
+
+	return otel.Resource{
+		ServiceName:           r.ServiceName,
+		ServiceVersion:        r.ServiceVersion,
+		DeploymentEnvironment: r.DeploymentEnvironment,
+		Attributes:            attrs,
+	}
+}
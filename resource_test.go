@@ -0,0 +1,85 @@
+package langfuse
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ezardev-team/langfuse-go/model"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestWithResource(t *testing.T) {
+	body, _ := captureOTLP(t, func(l *Langfuse) {
+		l.WithResource(Resource{
+			ServiceName:           "checkout",
+			ServiceVersion:        "1.4.2",
+			DeploymentEnvironment: "staging",
+			Process:               true,
+			Attributes: map[string]any{
+				"team":         "payments",
+				"shard":        3,
+				"service.name": "checkout-eu",
+			},
+		})
+		_, _ = l.Trace(&model.Trace{Name: "trace"})
+	})
+
+	var req coltracepb.ExportTraceServiceRequest
+	if err := proto.Unmarshal(body, &req); err != nil {
+		t.Fatalf("unmarshal OTLP body: %v", err)
+	}
+
+	attrs := map[string]*commonv1.AnyValue{}
+	for _, kv := range req.ResourceSpans[0].Resource.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+
+	for key, want := range map[string]string{
+		"service.name":                "checkout-eu",
+		"service.version":             "1.4.2",
+		"deployment.environment.name": "staging",
+		"team":                        "payments",
+		"process.runtime.name":        "go",
+		"telemetry.sdk.language":      "go",
+	} {
+		if got := attrs[key].GetStringValue(); got != want {
+			t.Errorf("%s=%q, want %q", key, got, want)
+		}
+	}
+	if got := attrs["shard"].GetIntValue(); got != 3 {
+		t.Errorf("shard=%d, want 3", got)
+	}
+	if got := attrs["process.pid"].GetIntValue(); got != int64(os.Getpid()) {
+		t.Errorf("process.pid=%d, want %d", got, os.Getpid())
+	}
+	if _, ok := attrs["host.name"]; ok {
+		t.Error("host attributes must only be added when Host is set")
+	}
+
+	scope := req.ResourceSpans[0].ScopeSpans[0].Scope
+	if scope.Name != "langfuse-go" || scope.Version == "" {
+		t.Errorf("scope=%v, want name and SDK version", scope)
+	}
+}
+
+func TestWithResource_Default(t *testing.T) {
+	body, _ := captureOTLP(t, func(l *Langfuse) {
+		_, _ = l.Trace(&model.Trace{Name: "trace"})
+	})
+
+	var req coltracepb.ExportTraceServiceRequest
+	if err := proto.Unmarshal(body, &req); err != nil {
+		t.Fatalf("unmarshal OTLP body: %v", err)
+	}
+
+	for _, kv := range req.ResourceSpans[0].Resource.Attributes {
+		if kv.Key == "service.name" && kv.Value.GetStringValue() != "langfuse-go" {
+			t.Errorf("service.name=%q, want langfuse-go", kv.Value.GetStringValue())
+		}
+		if kv.Key == "service.version" {
+			t.Error("service.version must be omitted when unset")
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package langfuse
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"slices"
+
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+// Sampler decides whether a trace is recorded. The decision is made once per
+// trace, by the first Trace call, observation, score or IsSampled query that
+// names it, and applies to every event of the trace. Observations created
+// before their trace only carry its ID, so rules on other trace fields see
+// them as an empty trace with that ID.
+type Sampler interface {
+	ShouldSample(trace *model.Trace) bool
+}
+
+// SamplerFunc adapts a function to a Sampler.
+type SamplerFunc func(trace *model.Trace) bool
+
+func (f SamplerFunc) ShouldSample(trace *model.Trace) bool {
+	return f(trace)
+}
+
+// RatioSampler samples ratio (0 to 1) of traces. The decision depends only
+// on the trace ID, so processes sharing a trace ID agree on it.
+func RatioSampler(ratio float64) Sampler {
+	return SamplerFunc(func(trace *model.Trace) bool {
+		switch {
+		case ratio >= 1:
+			return true
+		case ratio <= 0:
+			return false
+		}
+		sum := sha256.Sum256([]byte(trace.ID))
+		// The top 53 bits of the hash map the ID uniformly onto [0, 1).
+		return float64(binary.BigEndian.Uint64(sum[:8])>>11)/(1<<53) < ratio
+	})
+}
+
+// SamplingRule applies Sampler to the traces it matches. Every criterion
+// that is set must match; a rule without criteria matches every trace.
+type SamplingRule struct {
+	Name   string
+	UserID string
+	// Tag matches traces carrying the tag.
+	Tag string
+	// MetadataKey matches traces whose metadata has the top-level key and,
+	// if MetadataValue is set, whose value formats (with fmt.Sprint) to it.
+	MetadataKey   string
+	MetadataValue string
+	Sampler       Sampler
+}
+
+func (r SamplingRule) matches(trace *model.Trace) bool {
+	if r.Name != "" && trace.Name != r.Name {
+		return false
+	}
+	if r.UserID != "" && trace.UserID != r.UserID {
+		return false
+	}
+	if r.Tag != "" && !slices.Contains(trace.Tags, r.Tag) {
+		return false
+	}
+	if r.MetadataKey != "" {
+		value, ok := metadataValue(trace.Metadata, r.MetadataKey)
+		if !ok || (r.MetadataValue != "" && fmt.Sprint(value) != r.MetadataValue) {
+			return false
+		}
+	}
+	return true
+}
+
+func metadataValue(metadata any, key string) (any, bool) {
+	switch m := metadata.(type) {
+	case map[string]any:
+		value, ok := m[key]
+		return value, ok
+	case model.M:
+		value, ok := m[key]
+		return value, ok
+	case map[string]string:
+		value, ok := m[key]
+		return value, ok
+	}
+	return nil, false
+}
+
+// RuleSampler decides with the Sampler of the first rule matching the trace,
+// and with fallback when none does. A nil fallback samples the trace.
+func RuleSampler(fallback Sampler, rules ...SamplingRule) Sampler {
+	return SamplerFunc(func(trace *model.Trace) bool {
+		for _, rule := range rules {
+			if rule.matches(trace) {
+				return rule.Sampler == nil || rule.Sampler.ShouldSample(trace)
+			}
+		}
+		return fallback == nil || fallback.ShouldSample(trace)
+	})
+}
+
+// WithSampler sets s to decide which traces are recorded. Events of traces
+// it rejects, including their observations and scores, are dropped before
+// they are queued. By default every trace is recorded.
+func (l *Langfuse) WithSampler(s Sampler) *Langfuse {
+	l.sampler = s
+	return l
+}
+
+// SamplerProcessor returns an EventProcessor dropping the events of traces
+// s rejects, for sampling that must see the events as changed by the
+// processors registered before it. It decides once per trace, like
+// WithSampler, but its decisions are its own: IsSampled does not consult
+// them, and its drops are counted as Filtered rather than Unsampled.
+func SamplerProcessor(s Sampler) EventProcessor {
+	var d samplingDecisions
+
+	return EventProcessorFunc(func(_ context.Context, event *model.IngestionEvent) bool {
+		trace, ok := event.Body.(*model.Trace)
+		if !ok {
+			trace = &model.Trace{ID: eventTraceID(*event)}
+		}
+		if trace.ID == "" {
+			return true
+		}
+		return d.decide(trace.ID, func() bool { return s.ShouldSample(trace) })
+	})
+}
+
+// IsSampled reports whether the events of traceID are recorded. Use it to
+// skip building expensive payloads for traces that will be dropped.
+func (l *Langfuse) IsSampled(traceID string) bool {
+	return l.sampleTrace(&model.Trace{ID: traceID})
+}
+
+// sampleTrace returns the decision for trace, making it on first use.
+func (l *Langfuse) sampleTrace(trace *model.Trace) bool {
+	if l.sampler == nil || trace.ID == "" {
+		return true
+	}
+	return l.samplingDecisions.decide(trace.ID, func() bool {
+		return l.sampler.ShouldSample(trace)
+	})
+}
+
+// sampleEvent reports whether event belongs to a sampled trace.
+func (l *Langfuse) sampleEvent(event model.IngestionEvent) bool {
+	if trace, ok := event.Body.(*model.Trace); ok {
+		return l.sampleTrace(trace)
+	}
+	return l.IsSampled(eventTraceID(event))
+}
+
+// samplingDecisions remembers the decisions of the most recently used
+// traces. Every lookup refreshes a decision, so a trace still in use keeps
+// its decision however many traces start after it. Forgotten decisions are,
+// if needed again, remade from the trace ID alone.
+type samplingDecisions struct {
+	decisions recentMap[string, bool]
+}
+
+// decide returns the decision for traceID, calling sample to make it if
+// none is remembered.
+func (d *samplingDecisions) decide(traceID string, sample func() bool) bool {
+	return d.decisions.getOrSet(traceID, sample)
+}
+
+// keep records traceID as sampled regardless of the sampler.
+func (d *samplingDecisions) keep(traceID string) {
+	d.decisions.set(traceID, true)
+}
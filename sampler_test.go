@@ -0,0 +1,126 @@
+package langfuse
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/ezardev-team/langfuse-go/model"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRatioSampler(t *testing.T) {
+	sampler := RatioSampler(0.25)
+
+	sampled := 0
+	for i := range 10000 {
+		trace := &model.Trace{ID: fmt.Sprintf("trace-%d", i)}
+		decision := sampler.ShouldSample(trace)
+		if decision != sampler.ShouldSample(&model.Trace{ID: trace.ID, Name: "other"}) {
+			t.Fatalf("decision for %s is not consistent", trace.ID)
+		}
+		if decision {
+			sampled++
+		}
+	}
+	if ratio := float64(sampled) / 10000; math.Abs(ratio-0.25) > 0.03 {
+		t.Errorf("sampled ratio=%.3f, want about 0.25", ratio)
+	}
+
+	if !RatioSampler(1).ShouldSample(&model.Trace{ID: "a"}) || RatioSampler(0).ShouldSample(&model.Trace{ID: "a"}) {
+		t.Error("ratios 1 and 0 must keep and drop every trace")
+	}
+}
+
+func TestRuleSampler(t *testing.T) {
+	keep, drop := RatioSampler(1), RatioSampler(0)
+	sampler := RuleSampler(drop,
+		SamplingRule{Name: "checkout", Sampler: keep},
+		SamplingRule{UserID: "vip", Sampler: keep},
+		SamplingRule{Tag: "debug", Sampler: keep},
+		SamplingRule{MetadataKey: "tier", MetadataValue: "enterprise", Sampler: keep},
+		SamplingRule{MetadataKey: "canary"},
+	)
+
+	for _, tc := range []struct {
+		trace *model.Trace
+		want  bool
+	}{
+		{&model.Trace{Name: "checkout"}, true},
+		{&model.Trace{Name: "search", UserID: "vip"}, true},
+		{&model.Trace{Tags: []string{"a", "debug"}}, true},
+		{&model.Trace{Metadata: map[string]any{"tier": "enterprise"}}, true},
+		{&model.Trace{Metadata: model.M{"tier": "free"}}, false},
+		{&model.Trace{Metadata: map[string]string{"canary": ""}}, true},
+		{&model.Trace{Name: "search"}, false},
+	} {
+		if got := sampler.ShouldSample(tc.trace); got != tc.want {
+			t.Errorf("ShouldSample(%+v)=%v, want %v", tc.trace, got, tc.want)
+		}
+	}
+}
+
+func TestWithSampler_DropsUnsampledTraces(t *testing.T) {
+	var (
+		l       *Langfuse
+		dropped *model.Trace
+	)
+	body, _ := captureOTLP(t, func(lf *Langfuse) {
+		l = lf.WithSampler(RuleSampler(nil, SamplingRule{Name: "health-check", Sampler: RatioSampler(0)}))
+
+		kept, _ := l.Trace(&model.Trace{Name: "request"})
+		_, _ = l.Span(&model.Span{TraceID: kept.ID, Name: "kept-span"}, nil)
+
+		dropped, _ = l.Trace(&model.Trace{Name: "health-check"})
+		_, _ = l.Span(&model.Span{TraceID: dropped.ID, Name: "dropped-span"}, nil)
+		_, _ = l.Score(&model.Score{TraceID: dropped.ID, Name: "ok", Value: 1})
+	})
+
+	var req coltracepb.ExportTraceServiceRequest
+	if err := proto.Unmarshal(body, &req); err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]bool{}
+	for _, span := range req.ResourceSpans[0].ScopeSpans[0].Spans {
+		names[span.Name] = true
+	}
+	if !names["kept-span"] || names["dropped-span"] || names["health-check"] {
+		t.Errorf("exported spans=%v", names)
+	}
+
+	if l.IsSampled(dropped.ID) {
+		t.Error("IsSampled must report the dropped trace")
+	}
+	if stats := l.Stats(); stats.Unsampled != 3 || stats.Scores != 0 {
+		t.Errorf("stats=%+v, want the trace, span and score dropped", stats)
+	}
+}
+
+func TestSamplingDecisions_Bounded(t *testing.T) {
+	var d samplingDecisions
+	d.decide("long-lived", func() bool { return true })
+	for i := range defaultRecentEntries + 10 {
+		d.decide(fmt.Sprint(i), func() bool { return false })
+		if i%1000 == 0 && !d.decide("long-lived", func() bool { return false }) {
+			t.Fatal("a trace in use must keep its decision")
+		}
+	}
+	if n := len(d.decisions.entries); n != defaultRecentEntries {
+		t.Errorf("remembered %d decisions, want %d", n, defaultRecentEntries)
+	}
+	if _, ok := d.decisions.entries["0"]; ok {
+		t.Error("least recently used decision must be forgotten first")
+	}
+
+	d.keep("1")
+	if !d.decide("1", func() bool { return false }) {
+		t.Error("kept trace must stay sampled")
+	}
+}
+
+func TestIsSampled_NoSampler(t *testing.T) {
+	if !(&Langfuse{}).IsSampled("any") {
+		t.Error("every trace is sampled without a sampler")
+	}
+}
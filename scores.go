@@ -0,0 +1,167 @@
+package langfuse
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/ezardev-team/langfuse-go/internal/pkg/api"
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+// GetScore fetches a single score by ID.
+func (l *Langfuse) GetScore(ctx context.Context, id string) (*model.ScoreView, error) {
+	req := api.ScoreRequest{ID: id}
+	res := api.ScoreResponse{}
+
+	if err := l.client.Score(ctx, &req, &res); err != nil {
+		return nil, err
+	}
+
+	if !res.IsSuccess() {
+		return nil, newAPIError("score", &req, res.Code, res.RawBody)
+	}
+
+	return &res.Score, nil
+}
+
+// ScoreQuery selects scores for ListScores.
+type ScoreQuery struct {
+	UserID   string
+	Name     string
+	Source   model.ScoreSource
+	DataType model.ScoreDataType
+	ConfigID string
+	// FromTimestamp and ToTimestamp bound the score timestamp.
+	FromTimestamp *time.Time
+	ToTimestamp   *time.Time
+	Environment   []string
+	// TraceTags only matches scores whose trace carries every listed tag.
+	TraceTags []string
+	PageSize  int
+}
+
+// ListScores returns an iterator over the scores matching query.
+func (l *Langfuse) ListScores(ctx context.Context, query ScoreQuery) iter.Seq2[*model.ScoreView, error] {
+	return listPages(ctx, func(ctx context.Context, page int) ([]model.ScoreView, model.PageMeta, error) {
+		req := api.ScoresRequest{
+			Page:          page,
+			Limit:         query.PageSize,
+			UserID:        query.UserID,
+			Name:          query.Name,
+			Source:        query.Source,
+			DataType:      query.DataType,
+			ConfigID:      query.ConfigID,
+			FromTimestamp: query.FromTimestamp,
+			ToTimestamp:   query.ToTimestamp,
+			Environment:   query.Environment,
+			TraceTags:     query.TraceTags,
+		}
+		res := api.ScoresResponse{}
+		if err := l.client.Scores(ctx, &req, &res); err != nil {
+			return nil, model.PageMeta{}, err
+		}
+		if !res.IsSuccess() {
+			return nil, model.PageMeta{}, newAPIError("scores", &req, res.Code, res.RawBody)
+		}
+		return res.Data, res.Meta, nil
+	})
+}
+
+// GetScoreConfig fetches a score config by ID. Configs cannot be edited once
+// created, so results are cached for the lifetime of the client.
+func (l *Langfuse) GetScoreConfig(ctx context.Context, id string) (*model.ScoreConfig, error) {
+	if cached, ok := l.scoreConfigs.Load(id); ok {
+		return cached.(*model.ScoreConfig), nil
+	}
+
+	req := api.ScoreConfigRequest{ID: id}
+	res := api.ScoreConfigResponse{}
+
+	if err := l.client.ScoreConfig(ctx, &req, &res); err != nil {
+		return nil, err
+	}
+
+	if !res.IsSuccess() {
+		return nil, newAPIError("score config", &req, res.Code, res.RawBody)
+	}
+
+	config := res.Config
+	l.scoreConfigs.Store(id, &config)
+
+	return &config, nil
+}
+
+// ListScoreConfigs returns an iterator over all score configs of the project.
+func (l *Langfuse) ListScoreConfigs(ctx context.Context) iter.Seq2[*model.ScoreConfig, error] {
+	return listPages(ctx, func(ctx context.Context, page int) ([]model.ScoreConfig, model.PageMeta, error) {
+		req := api.ScoreConfigsRequest{Page: page}
+		res := api.ScoreConfigsResponse{}
+		if err := l.client.ScoreConfigs(ctx, &req, &res); err != nil {
+			return nil, model.PageMeta{}, err
+		}
+		if !res.IsSuccess() {
+			return nil, model.PageMeta{}, newAPIError("score configs", &req, res.Code, res.RawBody)
+		}
+		return res.Data, res.Meta, nil
+	})
+}
+
+// ValidateScore checks s before it is sent with Score. Scores linked to a
+// config (ConfigID) are checked against the config's data type, range and
+// categories, and take the config's data type when DataType is empty; others
+// are only checked against their own data type.
+func (l *Langfuse) ValidateScore(ctx context.Context, s *model.Score) error {
+	if s.ConfigID == "" {
+		return s.Validate()
+	}
+
+	config, err := l.GetScoreConfig(ctx, s.ConfigID)
+	if err != nil {
+		return err
+	}
+
+	if err := config.Validate(s); err != nil {
+		return err
+	}
+
+	if s.DataType == "" {
+		s.DataType = config.DataType
+	}
+
+	return nil
+}
+
+func scoreCreateRequest(s *model.Score) *api.ScoreCreateRequest {
+	req := &api.ScoreCreateRequest{
+		ID:            s.ID,
+		TraceID:       s.TraceID,
+		ObservationID: s.ObservationID,
+		Name:          s.Name,
+		Value:         s.Value,
+		DataType:      s.DataType,
+		Comment:       s.Comment,
+		ConfigID:      s.ConfigID,
+	}
+
+	if s.EffectiveDataType() == model.ScoreDataTypeCategorical {
+		req.Value = s.StringValue
+	}
+
+	return req
+}
+
+func createScore(ctx context.Context, client *api.Client, s *model.Score) error {
+	req := scoreCreateRequest(s)
+	res := api.ScoreCreateResponse{}
+
+	if err := client.CreateScore(ctx, req, &res); err != nil {
+		return err
+	}
+
+	if !res.IsSuccess() {
+		return newAPIError("score", req, res.Code, res.RawBody)
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package langfuse
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ezardev-team/langfuse-go/internal/pkg/api"
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+func TestListScores(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/public/v2/scores" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("name") != "tone" || q.Get("dataType") != "CATEGORICAL" || q.Get("source") != "EVAL" {
+			t.Errorf("unexpected query: %v", q)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"data": [{"id": "s-1", "name": "tone", "dataType": "CATEGORICAL", "stringValue": "polite", "source": "EVAL"}],
+			"meta": {"page": 1, "limit": 50, "totalItems": 1, "totalPages": 1}
+		}`))
+	}))
+	defer srv.Close()
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	var got []*model.ScoreView
+	for s, err := range l.ListScores(context.Background(), ScoreQuery{
+		Name:     "tone",
+		DataType: model.ScoreDataTypeCategorical,
+		Source:   model.ScoreSourceEval,
+	}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, s)
+	}
+
+	if len(got) != 1 || got[0].StringValue != "polite" || got[0].DataType != model.ScoreDataTypeCategorical {
+		t.Errorf("scores=%+v", got)
+	}
+}
+
+func TestGetScore(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/public/v2/scores/s-1" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "s-1", "name": "helpful", "dataType": "BOOLEAN", "value": 1, "stringValue": "True"}`))
+	}))
+	defer srv.Close()
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	score, err := l.GetScore(context.Background(), "s-1")
+	if err != nil {
+		t.Fatalf("GetScore: %v", err)
+	}
+	if score.ID != "s-1" || score.Value != 1 || score.DataType != model.ScoreDataTypeBoolean {
+		t.Errorf("score=%+v", score)
+	}
+}
+
+// TestValidateScore_UsesCachedConfig verifies values outside a config's range
+// are rejected and the config is fetched only once.
+func TestValidateScore_UsesCachedConfig(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path != "/api/public/score-configs/cfg-1" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "cfg-1", "name": "rating", "dataType": "NUMERIC", "minValue": 1, "maxValue": 5}`))
+	}))
+	defer srv.Close()
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	ok := &model.Score{Name: "rating", Value: 4, ConfigID: "cfg-1"}
+	if err := l.ValidateScore(context.Background(), ok); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok.DataType != model.ScoreDataTypeNumeric {
+		t.Errorf("DataType=%q, want it filled in from the config", ok.DataType)
+	}
+
+	err := l.ValidateScore(context.Background(), &model.Score{Name: "rating", Value: 7, ConfigID: "cfg-1"})
+	if err == nil || !strings.Contains(err.Error(), "maximum") {
+		t.Errorf("err=%v, want range error", err)
+	}
+
+	if n := requests.Load(); n != 1 {
+		t.Errorf("config requests=%d, want 1", n)
+	}
+}
+
+// TestIngest_PostsScores verifies score-create events go to the scores API
+// with values typed by data type, while other events still go out as OTLP.
+func TestIngest_PostsScores(t *testing.T) {
+	var mu sync.Mutex
+	var scoreBodies []map[string]any
+	var otlpRequests int
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.URL.Path {
+		case "/api/public/scores":
+			body, _ := io.ReadAll(r.Body)
+			var decoded map[string]any
+			if err := json.Unmarshal(body, &decoded); err != nil {
+				t.Errorf("decode score body: %v", err)
+			}
+			scoreBodies = append(scoreBodies, decoded)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":"x"}`))
+		case "/api/public/otel/v1/traces":
+			otlpRequests++
+			w.WriteHeader(http.StatusOK)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	t.Setenv("LANGFUSE_HOST", srv.URL)
+	l := &Langfuse{client: api.New()}
+
+	now := time.Now()
+	err := l.ingest(context.Background(), []model.IngestionEvent{
+		{Type: model.IngestionEventTypeTraceCreate, Timestamp: now, Body: &model.Trace{ID: "t-1", Name: "trace"}},
+		{Type: model.IngestionEventTypeScoreCreate, Timestamp: now, Body: &model.Score{
+			TraceID: "t-1", Name: "helpful", DataType: model.ScoreDataTypeBoolean, Value: 0,
+		}},
+		{Type: model.IngestionEventTypeScoreCreate, Timestamp: now, Body: &model.Score{
+			TraceID: "t-1", Name: "tone", DataType: model.ScoreDataTypeCategorical, StringValue: "polite",
+		}},
+		// A config-linked categorical score takes its data type from the
+		// config, so it may carry only the label.
+		{Type: model.IngestionEventTypeScoreCreate, Timestamp: now, Body: &model.Score{
+			TraceID: "t-1", Name: "tone", ConfigID: "cfg-tone", StringValue: "rude",
+		}},
+	})
+	if err != nil {
+		t.Fatalf("ingest: %v", err)
+	}
+
+	if otlpRequests != 1 {
+		t.Errorf("OTLP requests=%d, want 1", otlpRequests)
+	}
+	if len(scoreBodies) != 3 {
+		t.Fatalf("score requests=%d, want 3", len(scoreBodies))
+	}
+	if v, ok := scoreBodies[0]["value"]; !ok || v != 0.0 {
+		t.Errorf("boolean false must be sent as 0, body=%v", scoreBodies[0])
+	}
+	if scoreBodies[1]["value"] != "polite" || scoreBodies[1]["dataType"] != "CATEGORICAL" {
+		t.Errorf("categorical body=%v", scoreBodies[1])
+	}
+	if scoreBodies[2]["value"] != "rude" || scoreBodies[2]["configId"] != "cfg-tone" {
+		t.Errorf("config-linked categorical body=%v", scoreBodies[2])
+	}
+}
+
+func TestScore_StringValueWithoutDataType(t *testing.T) {
+	s := &model.Score{TraceID: "t", Name: "tone", ConfigID: "cfg-tone", StringValue: "polite"}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if req := scoreCreateRequest(s); req.Value != "polite" {
+		t.Errorf("value=%v, want the category label", req.Value)
+	}
+}
+
+func TestScore_RejectsInvalidValue(t *testing.T) {
+	l := &Langfuse{}
+	if _, err := l.Score(&model.Score{TraceID: "t", Name: "b", DataType: model.ScoreDataTypeBoolean, Value: 3}); err == nil {
+		t.Fatal("expected validation error")
+	}
+}
@@ -0,0 +1,143 @@
+package langfuse
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/ezardev-team/langfuse-go/internal/pkg/api"
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+// SessionDetail is a session read back from Langfuse with all of its traces.
+type SessionDetail struct {
+	Session model.SessionView
+	Traces  []model.TraceView
+}
+
+// GetSession fetches a session and the traces that belong to it.
+func (l *Langfuse) GetSession(ctx context.Context, id string) (*SessionDetail, error) {
+	req := api.SessionRequest{ID: id}
+	res := api.SessionResponse{}
+
+	if err := l.client.Session(ctx, &req, &res); err != nil {
+		return nil, err
+	}
+
+	if !res.IsSuccess() {
+		return nil, newAPIError("session", &req, res.Code, res.RawBody)
+	}
+
+	return &SessionDetail{
+		Session: res.Session,
+		Traces:  res.Traces,
+	}, nil
+}
+
+// SessionQuery selects sessions for ListSessions.
+type SessionQuery struct {
+	// FromTimestamp and ToTimestamp bound the session creation time.
+	FromTimestamp *time.Time
+	ToTimestamp   *time.Time
+	Environment   []string
+	PageSize      int
+}
+
+// ListSessions returns an iterator over the sessions matching query.
+func (l *Langfuse) ListSessions(ctx context.Context, query SessionQuery) iter.Seq2[*model.SessionView, error] {
+	return listPages(ctx, func(ctx context.Context, page int) ([]model.SessionView, model.PageMeta, error) {
+		req := api.SessionsRequest{
+			Page:          page,
+			Limit:         query.PageSize,
+			FromTimestamp: query.FromTimestamp,
+			ToTimestamp:   query.ToTimestamp,
+			Environment:   query.Environment,
+		}
+		res := api.SessionsResponse{}
+		if err := l.client.Sessions(ctx, &req, &res); err != nil {
+			return nil, model.PageMeta{}, err
+		}
+		if !res.IsSuccess() {
+			return nil, model.PageMeta{}, newAPIError("sessions", &req, res.Code, res.RawBody)
+		}
+		return res.Data, res.Meta, nil
+	})
+}
+
+// Session groups traces into one Langfuse session, e.g. the turns of a chat
+// conversation. Every trace created through it carries its ID, including the
+// traces created implicitly for observations without a TraceID.
+type Session struct {
+	id string
+	l  *Langfuse
+}
+
+// Session returns a handle for the session with the given ID. An empty id
+// starts a new session with a generated ID.
+func (l *Langfuse) Session(id string) *Session {
+	return &Session{id: buildID(&id), l: l}
+}
+
+// ID returns the session ID.
+func (s *Session) ID() string {
+	return s.id
+}
+
+// Trace creates a trace in the session, overriding t.SessionID.
+func (s *Session) Trace(t *model.Trace) (*model.Trace, error) {
+	t.SessionID = s.id
+	return s.l.Trace(t)
+}
+
+// Generation creates a generation. When g.TraceID is empty a new trace is
+// created in the session first.
+func (s *Session) Generation(g *model.Generation, parentID *string) (*model.Generation, error) {
+	if g.TraceID == "" {
+		traceID, err := s.createTrace(g.Name)
+		if err != nil {
+			return nil, err
+		}
+		g.TraceID = traceID
+	}
+	return s.l.Generation(g, parentID)
+}
+
+// Span creates a span. When sp.TraceID is empty a new trace is created in
+// the session first.
+func (s *Session) Span(sp *model.Span, parentID *string) (*model.Span, error) {
+	if sp.TraceID == "" {
+		traceID, err := s.createTrace(sp.Name)
+		if err != nil {
+			return nil, err
+		}
+		sp.TraceID = traceID
+	}
+	return s.l.Span(sp, parentID)
+}
+
+// Event creates an event. When e.TraceID is empty a new trace is created in
+// the session first.
+func (s *Session) Event(e *model.Event, parentID *string) (*model.Event, error) {
+	if e.TraceID == "" {
+		traceID, err := s.createTrace(e.Name)
+		if err != nil {
+			return nil, err
+		}
+		e.TraceID = traceID
+	}
+	return s.l.Event(e, parentID)
+}
+
+// Get fetches the session and its traces from Langfuse.
+func (s *Session) Get(ctx context.Context) (*SessionDetail, error) {
+	return s.l.GetSession(ctx, s.id)
+}
+
+func (s *Session) createTrace(traceName string) (string, error) {
+	trace, err := s.Trace(&model.Trace{Name: traceName})
+	if err != nil {
+		return "", err
+	}
+
+	return trace.ID, nil
+}
@@ -0,0 +1,135 @@
+package langfuse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ezardev-team/langfuse-go/model"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracev1 "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGetSession(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/public/sessions/chat-1" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": "chat-1",
+			"environment": "production",
+			"createdAt": "2024-01-01T00:00:00Z",
+			"traces": [{"id": "turn-1", "sessionId": "chat-1"}, {"id": "turn-2", "sessionId": "chat-1"}]
+		}`))
+	}))
+	defer srv.Close()
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	detail, err := l.Session("chat-1").Get(context.Background())
+	if err != nil {
+		t.Fatalf("GetSession: %v", err)
+	}
+	if detail.Session.ID != "chat-1" || detail.Session.Environment != "production" || detail.Session.CreatedAt == nil {
+		t.Errorf("session=%+v", detail.Session)
+	}
+	if len(detail.Traces) != 2 || detail.Traces[1].ID != "turn-2" {
+		t.Errorf("traces=%+v", detail.Traces)
+	}
+}
+
+func TestListSessions_FollowsPages(t *testing.T) {
+	var pagesSeen []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pagesSeen = append(pagesSeen, r.URL.Query().Get("page"))
+		if got := r.URL.Query().Get("environment"); got != "production" {
+			t.Errorf("environment=%q, want production", got)
+		}
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": []map[string]any{{"id": "s" + strconv.Itoa(page)}},
+			"meta": map[string]any{"page": page, "limit": 1, "totalItems": 2, "totalPages": 2},
+		})
+	}))
+	defer srv.Close()
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	var ids []string
+	for session, err := range l.ListSessions(context.Background(), SessionQuery{
+		Environment: []string{"production"},
+		PageSize:    1,
+	}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, session.ID)
+	}
+
+	if got := strings.Join(ids, ","); got != "s1,s2" {
+		t.Errorf("ids=%s, want s1,s2", got)
+	}
+	if got := strings.Join(pagesSeen, ","); got != "1,2" {
+		t.Errorf("pages=%s, want 1,2", got)
+	}
+}
+
+// TestSession_StampsSessionID verifies traces created through a Session,
+// explicitly or implicitly for an observation without a TraceID, carry the
+// session ID.
+func TestSession_StampsSessionID(t *testing.T) {
+	var sessionID string
+	body, _ := captureOTLP(t, func(lf *Langfuse) {
+		session := lf.Session("")
+		sessionID = session.ID()
+
+		tr, err := session.Trace(&model.Trace{Name: "turn-1", SessionID: "other"})
+		if err != nil {
+			t.Fatalf("Trace: %v", err)
+		}
+		if _, err := lf.Generation(&model.Generation{TraceID: tr.ID, Name: "answer"}, nil); err != nil {
+			t.Fatalf("Generation: %v", err)
+		}
+		if _, err := session.Span(&model.Span{Name: "turn-2"}, nil); err != nil {
+			t.Fatalf("Span: %v", err)
+		}
+	})
+
+	if sessionID == "" {
+		t.Fatal("Session(\"\") must generate an ID")
+	}
+
+	var req coltracepb.ExportTraceServiceRequest
+	if err := proto.Unmarshal(body, &req); err != nil {
+		t.Fatalf("unmarshal OTLP body: %v", err)
+	}
+	var spans []*tracev1.Span
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			spans = append(spans, ss.Spans...)
+		}
+	}
+
+	for _, want := range []struct{ name, obsType string }{
+		{"answer", "generation"},
+		{"turn-2", "span"},
+	} {
+		span := findObservationSpan(spans, want.name, want.obsType)
+		if span == nil {
+			t.Fatalf("span %s/%s not found", want.name, want.obsType)
+		}
+		if got, _ := spanAttr(span, "langfuse.session.id"); got != sessionID {
+			t.Errorf("%s: langfuse.session.id=%q, want %q", want.name, got, sessionID)
+		}
+	}
+}
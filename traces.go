@@ -0,0 +1,122 @@
+package langfuse
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/ezardev-team/langfuse-go/internal/pkg/api"
+	"github.com/ezardev-team/langfuse-go/model"
+)
+
+// TraceDetail is a trace read back from Langfuse with its observations
+// arranged as a parent/child tree and the scores attached to it.
+type TraceDetail struct {
+	Trace model.TraceView
+	// Observations holds the root observations; nested ones are reachable
+	// through ObservationNode.Children.
+	Observations []*model.ObservationNode
+	Scores       []model.ScoreView
+}
+
+// Observation returns the observation with the given ID, or nil.
+func (d *TraceDetail) Observation(id string) *model.ObservationView {
+	var found *model.ObservationView
+	for _, root := range d.Observations {
+		root.Walk(func(n *model.ObservationNode, _ int) bool {
+			if n.Observation.ID == id {
+				found = n.Observation
+			}
+			return found == nil
+		})
+		if found != nil {
+			break
+		}
+	}
+	return found
+}
+
+// GetTrace fetches a trace with all of its observations and scores.
+// Observations are listed through ListObservations and linked into a tree via
+// ParentObservationID; those whose parent is missing become roots.
+func (l *Langfuse) GetTrace(ctx context.Context, id string) (*TraceDetail, error) {
+	req := api.TraceRequest{ID: id}
+	res := api.TraceResponse{}
+
+	if err := l.client.Trace(ctx, &req, &res); err != nil {
+		return nil, err
+	}
+
+	if !res.IsSuccess() {
+		return nil, newAPIError("trace", &req, res.Code, res.RawBody)
+	}
+
+	query := ObservationQuery{TraceID: id}
+	if res.Trace.Environment != "" {
+		query.Environment = []string{res.Trace.Environment}
+	}
+
+	var observations []model.ObservationView
+	for obs, err := range l.ListObservations(ctx, query) {
+		if err != nil {
+			return nil, err
+		}
+		observations = append(observations, *obs)
+	}
+
+	return &TraceDetail{
+		Trace:        res.Trace,
+		Observations: model.BuildObservationTree(observations),
+		Scores:       res.Scores,
+	}, nil
+}
+
+// TraceQuery selects traces for ListTraces.
+type TraceQuery struct {
+	UserID    string
+	SessionID string
+	Name      string
+	// Tags only matches traces carrying every listed tag.
+	Tags        []string
+	Environment []string
+	Version     string
+	Release     string
+	// FromTimestamp and ToTimestamp bound the trace timestamp.
+	FromTimestamp *time.Time
+	ToTimestamp   *time.Time
+	// OrderBy is "<column>.<asc|desc>", e.g. "timestamp.desc".
+	OrderBy  string
+	PageSize int
+}
+
+func (q *TraceQuery) request(page int) api.TracesRequest {
+	return api.TracesRequest{
+		Page:          page,
+		Limit:         q.PageSize,
+		UserID:        q.UserID,
+		Name:          q.Name,
+		SessionID:     q.SessionID,
+		FromTimestamp: q.FromTimestamp,
+		ToTimestamp:   q.ToTimestamp,
+		OrderBy:       q.OrderBy,
+		Tags:          q.Tags,
+		Version:       q.Version,
+		Release:       q.Release,
+		Environment:   q.Environment,
+	}
+}
+
+// ListTraces returns an iterator over the traces matching query.
+func (l *Langfuse) ListTraces(ctx context.Context, query TraceQuery) iter.Seq2[*model.TraceView, error] {
+	return listPages(ctx, func(ctx context.Context, page int) ([]model.TraceView, model.PageMeta, error) {
+		req := query.request(page)
+		res := api.TracesResponse{}
+		if err := l.client.Traces(ctx, &req, &res); err != nil {
+			return nil, model.PageMeta{}, err
+		}
+		if !res.IsSuccess() {
+			return nil, model.PageMeta{}, newAPIError("traces", &req, res.Code, res.RawBody)
+		}
+		return res.Data, res.Meta, nil
+	})
+}
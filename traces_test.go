@@ -0,0 +1,156 @@
+package langfuse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGetTrace_BuildsObservationTree(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/public/traces/req-42":
+			_, _ = w.Write([]byte(`{
+				"id": "req-42",
+				"name": "checkout",
+				"userId": "u-1",
+				"observations": [{"id": "ignored"}],
+				"scores": [{"id": "s-1", "name": "quality", "value": 0.9, "observationId": "llm"}]
+			}`))
+		case "/api/public/v2/observations":
+			if got := r.URL.Query().Get("traceId"); got != "req-42" {
+				t.Errorf("traceId=%q, want req-42", got)
+			}
+			_, _ = w.Write([]byte(`{"data": [
+				{"id": "tool", "parentObservationId": "llm", "startTime": "2024-01-01T00:00:02Z"},
+				{"id": "root", "startTime": "2024-01-01T00:00:00Z"},
+				{"id": "llm", "parentObservationId": "root", "startTime": "2024-01-01T00:00:01Z", "providedModelName": "gpt-4o"}
+			], "meta": {"cursor": null}}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	detail, err := l.GetTrace(context.Background(), "req-42")
+	if err != nil {
+		t.Fatalf("GetTrace: %v", err)
+	}
+
+	if detail.Trace.ID != "req-42" || detail.Trace.Name != "checkout" || detail.Trace.UserID != "u-1" {
+		t.Errorf("trace=%+v", detail.Trace)
+	}
+	if len(detail.Scores) != 1 || detail.Scores[0].Name != "quality" || detail.Scores[0].Value != 0.9 {
+		t.Errorf("scores=%+v", detail.Scores)
+	}
+
+	if len(detail.Observations) != 1 {
+		t.Fatalf("roots=%d, want 1", len(detail.Observations))
+	}
+	root := detail.Observations[0]
+	if root.Observation.ID != "root" || len(root.Children) != 1 {
+		t.Fatalf("root=%+v", root.Observation)
+	}
+	llm := root.Children[0]
+	if llm.Observation.ID != "llm" || len(llm.Children) != 1 || llm.Children[0].Observation.ID != "tool" {
+		t.Errorf("unexpected subtree under root")
+	}
+
+	if obs := detail.Observation("llm"); obs == nil || obs.Model != "gpt-4o" {
+		t.Errorf("Observation(llm)=%+v", obs)
+	}
+	if detail.Observation("missing") != nil {
+		t.Error("Observation(missing) must be nil")
+	}
+}
+
+func TestGetTrace_NotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Trace not found"}`))
+	}))
+	defer srv.Close()
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	_, err := l.GetTrace(context.Background(), "nope")
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("err=%v, want 404 status error", err)
+	}
+}
+
+func TestListTraces_FollowsPages(t *testing.T) {
+	var mu sync.Mutex
+	var queries []url.Values
+	pages := [][]string{{"t1", "t2"}, {"t3"}}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.Query())
+		mu.Unlock()
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		data := []map[string]any{}
+		for _, id := range pages[page-1] {
+			data = append(data, map[string]any{"id": id})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": data,
+			"meta": map[string]any{"page": page, "limit": 2, "totalItems": 3, "totalPages": len(pages)},
+		})
+	}))
+	defer srv.Close()
+
+	l, cleanup := newTestLangfuseFromServer(t, srv)
+	defer cleanup()
+
+	var ids []string
+	for trace, err := range l.ListTraces(context.Background(), TraceQuery{
+		UserID:      "u-1",
+		SessionID:   "sess-1",
+		Tags:        []string{"prod", "eu"},
+		Environment: []string{"production"},
+		PageSize:    2,
+	}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, trace.ID)
+	}
+
+	if got := strings.Join(ids, ","); got != "t1,t2,t3" {
+		t.Errorf("ids=%s, want t1,t2,t3", got)
+	}
+	if len(queries) != 2 {
+		t.Fatalf("requests=%d, want 2", len(queries))
+	}
+
+	first := queries[0]
+	if first.Get("userId") != "u-1" || first.Get("sessionId") != "sess-1" || first.Get("limit") != "2" {
+		t.Errorf("first query=%v", first)
+	}
+	if got := strings.Join(first["tags"], ","); got != "prod,eu" {
+		t.Errorf("tags=%s, want prod,eu", got)
+	}
+	if first.Get("environment") != "production" {
+		t.Errorf("environment=%q", first.Get("environment"))
+	}
+	if queries[1].Get("page") != "2" {
+		t.Errorf("second page=%q, want 2", queries[1].Get("page"))
+	}
+}